@@ -0,0 +1,78 @@
+package scrape
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// BlockedError the site answers with an anti-bot challenge,errors.Is(e, ErrBlocked) is true
+type BlockedError struct {
+	Site   string //host of the blocked url
+	URL    string
+	Reason string
+}
+
+// Error ...
+func (e *BlockedError) Error() string {
+	return fmt.Sprintf("%s blocked by %s challenge,load the browser cookies of the site", e.Site, e.Reason)
+}
+
+// Is ...
+func (e *BlockedError) Is(target error) bool {
+	return target == ErrBlocked
+}
+
+type challengeMark struct {
+	reason string
+	marks  []string
+	paths  []string //the paths the site redirects to instead of the asked page
+	sites  []string //the sites the paths belong to,matched with the host of the final url
+	error  bool     //only check the error responses,the marks may appear on normal pages
+}
+
+var challengeMarks = []challengeMark{
+	{reason: "cloudflare", marks: []string{"cf-browser-verification", "challenge-form", "cf_chl_", "<title>Just a moment...</title>"}},
+	{reason: "ddos-guard", marks: []string{"<title>DDoS-Guard</title>", "ddos-guard.net/"}},
+	{reason: "captcha", marks: []string{"g-recaptcha", "h-captcha", "hcaptcha.com"}, error: true},
+	//the over 18 cookie of javdb is preset,it is only asked again with the redirect
+	{reason: "age-verify", paths: []string{"/doc/driver-verify"}, sites: []string{"javbus"}},
+	{reason: "age-verify", paths: []string{"/over18"}, sites: []string{"javdb"}},
+}
+
+// challengeSite check the host belongs to one of the sites,the mirrors have the site name in the host
+func challengeSite(host string, sites []string) bool {
+	host = strings.ToLower(host)
+	for _, site := range sites {
+		if strings.Contains(host, site) {
+			return true
+		}
+	}
+	return false
+}
+
+// challengeReason return which challenge the body or the final url after the redirects is,
+// empty when it is not a challenge
+func challengeReason(body []byte, status int, location string) string {
+	path, host := "", ""
+	if u, e := url.Parse(location); e == nil {
+		path, host = u.Path, u.Hostname()
+	}
+	for _, c := range challengeMarks {
+		if c.error && status == 200 {
+			continue
+		}
+		for _, p := range c.paths {
+			if path != "" && strings.HasPrefix(path, p) && challengeSite(host, c.sites) {
+				return c.reason
+			}
+		}
+		for _, mark := range c.marks {
+			if bytes.Contains(body, []byte(mark)) {
+				return c.reason
+			}
+		}
+	}
+	return ""
+}
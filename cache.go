@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sync"
 
 	"github.com/PuerkitoBio/goquery"
@@ -22,10 +23,31 @@ var DefaultCachePath = "tmp"
 var _cache *Cache
 var _cacheOnce *sync.Once
 
+// cookies always sent to a host, like the age check of some sites
+var siteCookies = make(map[string][]*http.Cookie)
+
+// statusErrorBodyLimit the size of the body kept in a StatusError
+const statusErrorBodyLimit = 64 << 10
+
+// StatusError the response status is not 200,the body is kept to find the reason
+type StatusError struct {
+	URL    string
+	Code   int
+	Status string
+	Body   []byte
+}
+
+// Error ...
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("status code error: %d %s", e.Code, e.Status)
+}
+
 // Cache ...
 type Cache struct {
 	lock  sync.Mutex
 	cache cacher.Cacher
+	path  string
+	jar   *cookieJar
 }
 
 func init() {
@@ -34,9 +56,12 @@ func init() {
 
 func newCache() *Cache {
 	cache.DefaultCachePath = DefaultCachePath
-	return &Cache{
+	c := &Cache{
 		cache: cache.New(),
+		path:  DefaultCachePath,
 	}
+	c.loadCookies()
+	return c
 }
 
 // NewCache ...
@@ -47,15 +72,18 @@ func NewCache() *Cache {
 	return _cache
 }
 
+// hashRegexp the keys made by Hash,the other keys of the cache are never served by hash
+var hashRegexp = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
 // Hash ...
 func Hash(url string) string {
 	sum256 := sha256.Sum256([]byte(url))
 	return fmt.Sprintf("%x", sum256)
 }
 
-// GetReader ...
+// GetReader get the url from the cache first,force get it again from the site
 func (c *Cache) GetReader(url string, force bool) (io.Reader, error) {
-	bys, e := c.get(url, force)
+	bys, e := c.get(url, !force)
 	if e != nil {
 		return nil, e
 	}
@@ -77,6 +105,12 @@ func (c *Cache) Get(url string) (bys []byte, e error) {
 }
 
 func (c *Cache) get(url string, useCache bool) (bys []byte, e error) {
+	bys, _, e = c.fetch(url, useCache)
+	return bys, e
+}
+
+// fetch return the data and the response,the response is nil when the data comes from the cache
+func (c *Cache) fetch(url string, useCache bool) (bys []byte, res *http.Response, e error) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
 	name := Hash(url)
@@ -86,9 +120,9 @@ func (c *Cache) get(url string, useCache bool) (bys []byte, e error) {
 		if e == nil && b {
 			getted, e := c.cache.Get(name)
 			if e != nil {
-				return nil, e
+				return nil, nil, e
 			}
-			return getted, nil
+			return getted, nil, nil
 		}
 	}
 	if cli == nil {
@@ -97,33 +131,104 @@ func (c *Cache) get(url string, useCache bool) (bys []byte, e error) {
 
 	req, err := http.NewRequest(http.MethodGet, url, nil)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	req.Header.Set("user-agent", UserAgent)
+	for _, cookie := range siteCookies[req.URL.Host] {
+		req.AddCookie(cookie)
+	}
+	for _, cookie := range c.jar.get(req.URL.Host) {
+		req.AddCookie(cookie)
 	}
-	req.Header.Set("user-agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/90.0.4430.11 Safari/537.36")
 
-	res, e := cli.Do(req)
+	res, e = cli.Do(req)
 	if e != nil {
-		return nil, e
+		return nil, nil, e
 	}
 	defer res.Body.Close()
 	if res.StatusCode != 200 {
-		return nil, fmt.Errorf("status code error: %d %s", res.StatusCode, res.Status)
+		body, _ := ioutil.ReadAll(io.LimitReader(res.Body, statusErrorBodyLimit))
+		if reason := challengeReason(body, res.StatusCode, res.Request.URL.String()); reason != "" {
+			return nil, nil, &BlockedError{Site: req.URL.Host, URL: url, Reason: reason}
+		}
+		return nil, nil, &StatusError{URL: url, Code: res.StatusCode, Status: res.Status, Body: body}
 	}
 	bys, e = ioutil.ReadAll(res.Body)
 	if e != nil {
-		return nil, e
+		return nil, nil, e
+	}
+	//the challenge page is never cached
+	if reason := challengeReason(bys, res.StatusCode, res.Request.URL.String()); reason != "" {
+		return nil, nil, &BlockedError{Site: req.URL.Host, URL: url, Reason: reason}
 	}
 	e = c.cache.Set(name, bys)
+	if e != nil {
+		return nil, nil, e
+	}
+	return bys, res, nil
+}
+
+// GetImage get the image and validate it,an invalid image is evicted from the cache
+func (c *Cache) GetImage(url string, force bool) ([]byte, error) {
+	bys, res, e := c.fetch(url, !force)
 	if e != nil {
 		return nil, e
 	}
+	location := url
+	contentType := ""
+	if res != nil {
+		location = res.Request.URL.String()
+		contentType = res.Header.Get("Content-Type")
+	}
+	e = ValidateImage(bys, contentType, location)
+	if e != nil {
+		log.Warnw("cache image", "url", url, "location", location, "evict", true, "error", e)
+		c.Delete(url)
+		return nil, fmt.Errorf("%s: %w", url, e)
+	}
 	return bys, nil
 }
 
-// Save ...
-func (c *Cache) Save(url, to string) (e error) {
+// Delete remove the cached data of the url
+func (c *Cache) Delete(url string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	e := c.cache.Delete(Hash(url))
+	if e != nil {
+		log.Errorw("cache delete", "url", url, "error", e)
+	}
+}
+
+// GetHash get the cached data by the hash of the url
+func (c *Cache) GetHash(hash string) ([]byte, error) {
+	if !hashRegexp.MatchString(hash) {
+		return nil, fmt.Errorf("%s is not a hash: %w", hash, ErrNotFound)
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.cache.Get(hash)
+}
+
+// getKey get the cached data of the key
+func (c *Cache) getKey(key string) ([]byte, bool) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
+	bys, e := c.cache.Get(key)
+	if e != nil {
+		return nil, false
+	}
+	return bys, true
+}
+
+// setKey ...
+func (c *Cache) setKey(key string, bys []byte) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.cache.Set(key, bys)
+}
+
+// Save ...
+func (c *Cache) Save(url, to string) (e error) {
 	s, e := filepath.Abs(to)
 	if e != nil {
 		return e
@@ -142,7 +247,7 @@ func (c *Cache) Save(url, to string) (e error) {
 	return nil
 }
 
-// Query ...
+// Query get the url like GetReader and parse the document
 func (c *Cache) Query(url string, force bool) (*goquery.Document, error) {
 	closer, e := c.GetReader(url, force)
 	if e != nil {
@@ -151,14 +256,16 @@ func (c *Cache) Query(url string, force bool) (*goquery.Document, error) {
 	return goquery.NewDocumentFromReader(closer)
 }
 
+// BaseQuery ...
 func (c *Cache) BaseQuery(url string) (*goquery.Document, error) {
-	closer, e := c.GetReader(url, true)
+	closer, e := c.GetReader(url, false)
 	if e != nil {
 		return nil, e
 	}
 	return goquery.NewDocumentFromReader(closer)
 }
 
+// ForceQuery ...
 func (c *Cache) ForceQuery(url string) (*goquery.Document, error) {
 	closer, e := c.GetReader(url, true)
 	if e != nil {
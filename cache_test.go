@@ -0,0 +1,61 @@
+package scrape
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// testClient restore the client after the test,the proxy of a test is not used by the others
+func testClient(t *testing.T) {
+	client := cli
+	t.Cleanup(func() {
+		cli = client
+	})
+}
+
+// testCache open the cache of the test in a temporary directory instead of the package directory,
+// the test does not use a proxy registered before
+func testCache(t *testing.T) *Cache {
+	testClient(t)
+	cli = nil
+	once, c, path := _cacheOnce, _cache, DefaultCachePath
+	DefaultCachePath = t.TempDir()
+	_cacheOnce = &sync.Once{}
+	t.Cleanup(func() {
+		_cacheOnce, _cache, DefaultCachePath = once, c, path
+	})
+	return NewCache()
+}
+
+// TestCacheForce ...
+func TestCacheForce(t *testing.T) {
+	c := testCache(t)
+	page := "old"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "<html><body>%s</body></html>", page)
+	}))
+	defer server.Close()
+
+	for _, test := range []struct {
+		page  string
+		force bool
+		want  string
+	}{
+		{"old", false, "old"},
+		{"new", false, "old"},
+		{"new", true, "new"},
+		{"newer", false, "new"},
+	} {
+		page = test.page
+		document, e := c.Query(server.URL, test.force)
+		if e != nil {
+			t.Fatal(e)
+		}
+		if v := document.Find("body").Text(); v != test.want {
+			t.Errorf("force %v: got %s want %s", test.force, v, test.want)
+		}
+	}
+}
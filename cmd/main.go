@@ -1,5 +1,183 @@
 package main
 
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	scrape "github.com/javscrape/go-scrape"
+)
+
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(s string) error {
+	*l = append(*l, s)
+	return nil
+}
+
+var commands = map[string]func(args []string) error{
+	"watch":   watch,
+	"serve":   serve,
+	"refresh": refresh,
+	"cookies": cookies,
+}
+
 func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, b := commands[os.Args[1]]
+	if !b {
+		usage()
+		os.Exit(2)
+	}
+	if e := cmd(os.Args[2:]); e != nil {
+		fmt.Fprintln(os.Stderr, e)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: scrape <command> [arguments]")
+	fmt.Fprintln(os.Stderr, "commands:")
+	for name := range commands {
+		fmt.Fprintln(os.Stderr, "\t"+name)
+	}
+}
+
+// newScrape create the scrape with the common flags,
+// javdb logs in with the account in JAVDB_EMAIL and JAVDB_PASSWORD when they are set
+func newScrape(proxy string) (scrape.IScrape, error) {
+	if proxy != "" {
+		if e := scrape.RegisterProxy(proxy); e != nil {
+			return nil, e
+		}
+	}
+	return scrape.NewScrape(
+		scrape.GrabOption(scrape.NewGrabJavbus()),
+		scrape.GrabOption(scrape.NewGrabJavdb(scrape.JavdbLogin(os.Getenv("JAVDB_EMAIL"), os.Getenv("JAVDB_PASSWORD")))),
+		scrape.ExactOption(true),
+	), nil
+}
+
+func watch(args []string) error {
+	var dirs stringList
+	set := flag.NewFlagSet("watch", flag.ExitOnError)
+	set.Var(&dirs, "dir", "directory to watch,can be set multiple times")
+	proxy := set.String("proxy", "", "proxy address")
+	poll := set.Duration("poll", 0, "scan the directories every duration instead of using fsnotify")
+	debounce := set.Duration("debounce", 30*time.Second, "wait until the file size is unchanged for the duration")
+	state := set.String("state", "", "state file path")
+	if e := set.Parse(args); e != nil {
+		return e
+	}
+	if len(dirs) == 0 {
+		return fmt.Errorf("no directory to watch")
+	}
+	s, e := newScrape(*proxy)
+	if e != nil {
+		return e
+	}
+	opts := []scrape.WatchOptions{scrape.WatchDebounce(*debounce)}
+	if *poll > 0 {
+		opts = append(opts, scrape.WatchPolling(*poll))
+	}
+	if *state != "" {
+		opts = append(opts, scrape.WatchStateFile(*state))
+	}
+	w := scrape.NewWatcher(s, dirs, opts...)
+	go func() {
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, os.Interrupt)
+		<-c
+		w.Stop()
+	}()
+	return w.Start()
+}
+
+func serve(args []string) error {
+	set := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := set.String("addr", ":8080", "listen address")
+	proxy := set.String("proxy", "", "proxy address")
+	jellyfin := set.Bool("jellyfin", false, "serve the jellyfin provider api under /jellyfin/")
+	if e := set.Parse(args); e != nil {
+		return e
+	}
+	s, e := newScrape(*proxy)
+	if e != nil {
+		return e
+	}
+	return scrape.NewServer(s, scrape.ServerJellyfin(*jellyfin)).ListenAndServe(*addr)
+}
+
+func refresh(args []string) error {
+	set := flag.NewFlagSet("refresh", flag.ExitOnError)
+	root := set.String("root", scrape.DefaultOutputPath, "root path of the info files")
+	proxy := set.String("proxy", "", "proxy address")
+	days := set.Int("days", 30, "refresh the records scraped more than days ago")
+	every := set.Duration("every", 0, "refresh every duration instead of once")
+	policy := set.String("policy", scrape.PolicyKeepManual.String(), "overwrite, fill-empty or keep-manual")
+	if e := set.Parse(args); e != nil {
+		return e
+	}
+	p, e := scrape.ParseMergePolicy(*policy)
+	if e != nil {
+		return e
+	}
+	s, e := newScrape(*proxy)
+	if e != nil {
+		return e
+	}
+	r := scrape.NewRefresher(s,
+		scrape.RefreshRoot(*root),
+		scrape.RefreshAge(time.Duration(*days)*24*time.Hour),
+		scrape.RefreshPolicy(p),
+		scrape.RefreshReport(func(result *scrape.RefreshResult) {
+			if result.Error != nil {
+				fmt.Printf("%s(%s): %v\n", result.ID, result.Reason, result.Error)
+				return
+			}
+			fmt.Printf("(%s)%s", result.Reason, result.Diff)
+		}),
+	)
+	if *every > 0 {
+		return r.Start(*every)
+	}
+	_, e = r.Refresh()
+	return e
+}
 
+func cookies(args []string) error {
+	set := flag.NewFlagSet("cookies", flag.ExitOnError)
+	file := set.String("import", "", "import a netscape cookies.txt or json cookie file exported from the browser")
+	remove := set.String("clear", "", "remove the cookies of the host,\"*\" removes every cookie")
+	if e := set.Parse(args); e != nil {
+		return e
+	}
+	c := scrape.NewCache()
+	switch {
+	case *file != "":
+		count, e := c.ImportCookiesFile(*file)
+		if e != nil {
+			return e
+		}
+		fmt.Printf("%d cookies imported\n", count)
+	case *remove == "*":
+		return c.ClearCookies("")
+	case *remove != "":
+		return c.ClearCookies(*remove)
+	default:
+		for _, host := range c.CookieHosts() {
+			fmt.Printf("%s: %d cookies\n", host, len(c.Cookies(host)))
+		}
+	}
+	return nil
 }
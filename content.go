@@ -1,6 +1,11 @@
 package scrape
 
-import "time"
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
 
 // Genre ...
 type Genre struct {
@@ -24,24 +29,109 @@ type Star struct {
 	Alias []string //other name(katakana,...)
 }
 
+// Review ...
+type Review struct {
+	User  string
+	Score float64
+	Date  string
+	Text  string
+}
+
+// Magnet ...
+type Magnet struct {
+	Name     string
+	Link     string
+	Size     string
+	Date     string
+	HD       bool
+	Subtitle bool
+}
+
+// Localized ...
+type Localized struct {
+	Language string
+	Title    string
+	Plot     string
+	Genres   []*Genre
+}
+
 // Content ...
 type Content struct {
 	From          string //where this
 	Language      string
 	Uncensored    bool
 	ID            string
+	ProductID     string `json:",omitempty"` //the number of the source when it is not the id,like 300MIUM-123 of MIUM-123
 	Title         string
 	OriginalTitle string
 	Year          string
 	ReleaseDate   time.Time
 	Studio        string
 	Director      string
+	Runtime       int //minutes
 	MovieSet      string
 	Plot          string
+	PlotFrom      string //which source the plot comes from
 	Genres        []*Genre
 	Actors        []*Star
 	Poster        string
 	Thumb         string
 	Sample        []*Sample
 	Publisher     string
+	Rating        float64               //user rating of the source,0 is unknown
+	Reviews       []*Review             `json:",omitempty"` //top user reviews of the source
+	UserRating    float64               `json:",omitempty"` //rating given by the logged in account of the source
+	Magnets       []*Magnet             `json:",omitempty"` //magnet links,some sites only show them to members
+	Preview       string                `json:",omitempty"` //preview video of the source
+	Localized     map[string]*Localized //key is the language name
+	Scraped       time.Time             //when the content was scraped,the stale info files are refreshed
+	Checksums     map[string]string     `json:",omitempty"` //field checksums when written,used to find manual edits
+	Locked        []string              `json:",omitempty"` //fields kept when the info file is written again,"*" locks all
+}
+
+// lockedFieldAlias the jellyfin/kodi names of the lockable fields
+var lockedFieldAlias = map[string]string{
+	"name":     "Title",
+	"overview": "Plot",
+	"studios":  "Studio",
+	"cast":     "Actors",
+}
+
+// IsLocked ...
+func (c *Content) IsLocked(field string) bool {
+	for _, locked := range c.Locked {
+		locked = strings.TrimSpace(locked)
+		if v, b := lockedFieldAlias[strings.ToLower(locked)]; b {
+			locked = v
+		}
+		if locked == "*" || strings.EqualFold(locked, field) {
+			return true
+		}
+	}
+	return false
+}
+
+var runtimeClockRegexp = regexp.MustCompile(`(\d{1,2}):(\d{2}):(\d{2})`)
+var runtimeNumberRegexp = regexp.MustCompile(`\d+`)
+var scoreRegexp = regexp.MustCompile(`\d+(?:\.\d+)?`)
+
+// parseRuntime return the minutes of a length like "120分鐘","120 min" or "01:58:30"
+func parseRuntime(length string) int {
+	if match := runtimeClockRegexp.FindStringSubmatch(length); match != nil {
+		h, _ := strconv.Atoi(match[1])
+		m, _ := strconv.Atoi(match[2])
+		s, _ := strconv.Atoi(match[3])
+		if s >= 30 {
+			m++
+		}
+		return h*60 + m
+	}
+	minutes, _ := strconv.Atoi(runtimeNumberRegexp.FindString(length))
+	return minutes
+}
+
+// parseScore return the first number of a score like "(8.50)" or "4.47分, 由1014人評價"
+func parseScore(score string) float64 {
+	f, _ := strconv.ParseFloat(scoreRegexp.FindString(score), 64)
+	return f
 }
@@ -0,0 +1,319 @@
+package scrape
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goextension/log"
+)
+
+// cookieFileName the file of the persisted cookies in the cache path,
+// the cookies are kept out of the cache store so they are never served with the cached data
+const cookieFileName = "cookies.json"
+
+// cookieJarKey the cache key the cookies were persisted with before,moved to the cookie file when loaded
+const cookieJarKey = "cookie.jar"
+
+// UserAgent the user agent of every request,
+// set it to the user agent of the browser the cookies come from,the challenge cookies are bound to it
+var UserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/90.0.4430.11 Safari/537.36"
+
+// jsonCookie the cookie exported by the browser extensions like EditThisCookie
+type jsonCookie struct {
+	Domain         string  `json:"domain"`
+	Name           string  `json:"name"`
+	Value          string  `json:"value"`
+	Path           string  `json:"path"`
+	ExpirationDate float64 `json:"expirationDate"`
+	Expires        float64 `json:"expires"`
+	Secure         bool    `json:"secure"`
+	HTTPOnly       bool    `json:"httpOnly"`
+}
+
+// cookieJar the cookies of every host,the key is the domain without the leading dot
+type cookieJar struct {
+	lock    sync.RWMutex
+	cookies map[string][]*http.Cookie
+}
+
+func newCookieJar() *cookieJar {
+	return &cookieJar{
+		cookies: make(map[string][]*http.Cookie),
+	}
+}
+
+// set replace the cookies of the same name
+func (j *cookieJar) set(domain string, cookies ...*http.Cookie) {
+	domain = cookieDomain(domain)
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	list := j.cookies[domain]
+	for _, cookie := range cookies {
+		replaced := false
+		for i, v := range list {
+			if v.Name == cookie.Name && v.Path == cookie.Path {
+				list[i] = cookie
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			list = append(list, cookie)
+		}
+	}
+	j.cookies[domain] = list
+}
+
+// get return the unexpired cookies of the host and its parent domains
+func (j *cookieJar) get(host string) []*http.Cookie {
+	host = cookieDomain(host)
+	if i := strings.LastIndex(host, ":"); i >= 0 && !strings.Contains(host, "]") {
+		host = host[:i]
+	}
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+	now := time.Now()
+	var cookies []*http.Cookie
+	for domain, list := range j.cookies {
+		if host != domain && !strings.HasSuffix(host, "."+domain) {
+			continue
+		}
+		for _, cookie := range list {
+			if !cookie.Expires.IsZero() && cookie.Expires.Before(now) {
+				continue
+			}
+			cookies = append(cookies, cookie)
+		}
+	}
+	return cookies
+}
+
+func (j *cookieJar) clear(host string) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	if host == "" {
+		j.cookies = make(map[string][]*http.Cookie)
+		return
+	}
+	delete(j.cookies, cookieDomain(host))
+}
+
+func (j *cookieJar) hosts() []string {
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+	var hosts []string
+	for host := range j.cookies {
+		hosts = append(hosts, host)
+	}
+	return hosts
+}
+
+func (j *cookieJar) marshal() ([]byte, error) {
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+	return json.Marshal(j.cookies)
+}
+
+func (j *cookieJar) unmarshal(bys []byte) error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	return json.Unmarshal(bys, &j.cookies)
+}
+
+func cookieDomain(domain string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(domain), "."))
+}
+
+// ParseCookies parse the cookies exported from a browser,
+// the netscape cookies.txt format or the json format of the browser extensions,the key is the domain
+func ParseCookies(bys []byte) (map[string][]*http.Cookie, error) {
+	trimmed := bytes.TrimSpace(bys)
+	if len(trimmed) > 0 && (trimmed[0] == '[' || trimmed[0] == '{') {
+		return parseJSONCookies(trimmed)
+	}
+	return parseNetscapeCookies(bytes.NewReader(bys))
+}
+
+func parseJSONCookies(bys []byte) (map[string][]*http.Cookie, error) {
+	var list []jsonCookie
+	if bys[0] == '{' {
+		//some extensions wrap the list like {"cookies":[...]}
+		var wrap struct {
+			Cookies []jsonCookie `json:"cookies"`
+		}
+		if e := json.Unmarshal(bys, &wrap); e != nil {
+			return nil, e
+		}
+		list = wrap.Cookies
+	} else if e := json.Unmarshal(bys, &list); e != nil {
+		return nil, e
+	}
+	cookies := make(map[string][]*http.Cookie)
+	for _, c := range list {
+		if c.Domain == "" || c.Name == "" {
+			continue
+		}
+		cookie := &http.Cookie{
+			Name:     c.Name,
+			Value:    c.Value,
+			Path:     c.Path,
+			Domain:   c.Domain,
+			Secure:   c.Secure,
+			HttpOnly: c.HTTPOnly,
+		}
+		expires := c.ExpirationDate
+		if expires == 0 {
+			expires = c.Expires
+		}
+		if expires > 0 {
+			cookie.Expires = time.Unix(int64(expires), 0)
+		}
+		domain := cookieDomain(c.Domain)
+		cookies[domain] = append(cookies[domain], cookie)
+	}
+	return cookies, nil
+}
+
+// parseNetscapeCookies the tab separated fields are domain,subdomains,path,secure,expires,name and value
+func parseNetscapeCookies(r io.Reader) (map[string][]*http.Cookie, error) {
+	cookies := make(map[string][]*http.Cookie)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		httpOnly := false
+		if strings.HasPrefix(line, "#HttpOnly_") {
+			line = strings.TrimPrefix(line, "#HttpOnly_")
+			httpOnly = true
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) < 7 {
+			return nil, errors.New("wrong netscape cookie line: " + line)
+		}
+		cookie := &http.Cookie{
+			Domain:   fields[0],
+			Path:     fields[2],
+			Secure:   strings.EqualFold(fields[3], "TRUE"),
+			Name:     fields[5],
+			Value:    fields[6],
+			HttpOnly: httpOnly,
+		}
+		if expires, e := strconv.ParseInt(fields[4], 10, 64); e == nil && expires > 0 {
+			cookie.Expires = time.Unix(expires, 0)
+		}
+		domain := cookieDomain(fields[0])
+		cookies[domain] = append(cookies[domain], cookie)
+	}
+	return cookies, scanner.Err()
+}
+
+func (c *Cache) cookiePath() string {
+	return filepath.Join(c.path, cookieFileName)
+}
+
+// loadCookies read the persisted cookies from the cookie file
+func (c *Cache) loadCookies() {
+	c.jar = newCookieJar()
+	bys, e := ioutil.ReadFile(c.cookiePath())
+	if os.IsNotExist(e) {
+		bys, e = c.moveCookies()
+	}
+	if e != nil {
+		log.Errorw("cookie load", "path", c.cookiePath(), "error", e)
+		return
+	}
+	if len(bys) == 0 {
+		return
+	}
+	if e := c.jar.unmarshal(bys); e != nil {
+		log.Errorw("cookie load", "error", e)
+	}
+}
+
+// moveCookies move the cookies persisted in the cache store to the cookie file
+func (c *Cache) moveCookies() ([]byte, error) {
+	bys, b := c.getKey(cookieJarKey)
+	if !b || len(bys) == 0 {
+		return nil, nil
+	}
+	if e := c.writeCookies(bys); e != nil {
+		return nil, e
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return bys, c.cache.Delete(cookieJarKey)
+}
+
+func (c *Cache) saveCookies() error {
+	bys, e := c.jar.marshal()
+	if e != nil {
+		return e
+	}
+	return c.writeCookies(bys)
+}
+
+// writeCookies the cookie file is only readable by the owner,it has the login sessions
+func (c *Cache) writeCookies(bys []byte) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	_ = os.MkdirAll(c.path, os.ModePerm)
+	return ioutil.WriteFile(c.cookiePath(), bys, 0600)
+}
+
+// SetCookies set the cookies sent to the host and its subdomains,the cookies are persisted in the cache path
+func (c *Cache) SetCookies(host string, cookies ...*http.Cookie) error {
+	c.jar.set(host, cookies...)
+	return c.saveCookies()
+}
+
+// ImportCookies import the cookies exported from a browser,see ParseCookies
+func (c *Cache) ImportCookies(bys []byte) (int, error) {
+	cookies, e := ParseCookies(bys)
+	if e != nil {
+		return 0, e
+	}
+	count := 0
+	for domain, list := range cookies {
+		c.jar.set(domain, list...)
+		count += len(list)
+	}
+	return count, c.saveCookies()
+}
+
+// ImportCookiesFile ...
+func (c *Cache) ImportCookiesFile(path string) (int, error) {
+	bys, e := ioutil.ReadFile(path)
+	if e != nil {
+		return 0, e
+	}
+	return c.ImportCookies(bys)
+}
+
+// Cookies return the cookies sent to the host
+func (c *Cache) Cookies(host string) []*http.Cookie {
+	return c.jar.get(host)
+}
+
+// CookieHosts return the hosts having cookies
+func (c *Cache) CookieHosts() []string {
+	return c.jar.hosts()
+}
+
+// ClearCookies remove the cookies of the host,every cookie is removed with an empty host
+func (c *Cache) ClearCookies(host string) error {
+	c.jar.clear(host)
+	return c.saveCookies()
+}
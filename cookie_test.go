@@ -0,0 +1,170 @@
+package scrape
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testNetscapeCookies = `# Netscape HTTP Cookie File
+.javlibrary.com	TRUE	/	TRUE	4102444800	cf_clearance	clearance
+#HttpOnly_www.javlibrary.com	FALSE	/	FALSE	0	session	abc
+.expired.com	TRUE	/	FALSE	1	old	value
+`
+
+const testJSONCookies = `[{"domain":".javdb.com","name":"_jdb_session","value":"session","path":"/","expirationDate":4102444800,"httpOnly":true}]`
+
+// TestParseCookies ...
+func TestParseCookies(t *testing.T) {
+	cookies, e := ParseCookies([]byte(testNetscapeCookies))
+	if e != nil {
+		t.Fatal(e)
+	}
+	if len(cookies["javlibrary.com"]) != 1 || len(cookies["www.javlibrary.com"]) != 1 || !cookies["www.javlibrary.com"][0].HttpOnly {
+		t.Fatalf("%+v", cookies)
+	}
+	cookies, e = ParseCookies([]byte(testJSONCookies))
+	if e != nil {
+		t.Fatal(e)
+	}
+	if len(cookies["javdb.com"]) != 1 || cookies["javdb.com"][0].Expires.Year() != 2100 {
+		t.Fatalf("%+v", cookies)
+	}
+	if _, e := ParseCookies([]byte("javdb.com\tTRUE")); e == nil {
+		t.Fatal("wrong line is parsed")
+	}
+
+	jar := newCookieJar()
+	all, _ := ParseCookies([]byte(testNetscapeCookies))
+	for domain, list := range all {
+		jar.set(domain, list...)
+	}
+	if v := jar.get("www.javlibrary.com"); len(v) != 2 {
+		t.Fatal(v)
+	}
+	if v := jar.get("javlibrary.com:443"); len(v) != 1 {
+		t.Fatal(v)
+	}
+	if v := jar.get("expired.com"); len(v) != 0 {
+		t.Fatal("expired cookie is sent", v)
+	}
+	if v := jar.get("notjavlibrary.com"); len(v) != 0 {
+		t.Fatal(v)
+	}
+}
+
+// TestCacheCookies ...
+func TestCacheCookies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, e := r.Cookie("cf_clearance"); e != nil {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `<html><head><title>Just a moment...</title></head></html>`)
+			return
+		}
+		fmt.Fprint(w, "<html>page</html>")
+	}))
+	defer server.Close()
+	c := testCache(t)
+
+	url := server.URL + "/cookie/" + time.Now().String()
+	_, e := c.ForceGet(url)
+	var blocked *BlockedError
+	if !errors.Is(e, ErrBlocked) || !errors.As(e, &blocked) || blocked.Reason != "cloudflare" {
+		t.Fatal(e)
+	}
+	e = c.SetCookies("127.0.0.1", &http.Cookie{Name: "cf_clearance", Value: "clearance"})
+	if e != nil {
+		t.Fatal(e)
+	}
+	//the cookies come back from the cookie file,not the cache store
+	info, e := os.Stat(filepath.Join(DefaultCachePath, cookieFileName))
+	if e != nil || info.Mode().Perm() != 0600 {
+		t.Fatal(info, e)
+	}
+	if _, b := c.getKey(cookieJarKey); b {
+		t.Fatal("the cookies are in the cache store")
+	}
+	c.loadCookies()
+	if _, e := c.ForceGet(url); e != nil {
+		t.Fatal(e)
+	}
+}
+
+// TestCacheCookiesMoved ...
+func TestCacheCookiesMoved(t *testing.T) {
+	c := testCache(t)
+	if e := c.setKey(cookieJarKey, []byte(`{"javdb.com":[{"Name":"remember_me_token","Value":"token"}]}`)); e != nil {
+		t.Fatal(e)
+	}
+	c.loadCookies()
+	if v := c.Cookies("javdb.com"); len(v) != 1 || v[0].Value != "token" {
+		t.Fatal(v)
+	}
+	if _, b := c.getKey(cookieJarKey); b {
+		t.Fatal("the cookies are kept in the cache store")
+	}
+	if _, e := os.Stat(filepath.Join(DefaultCachePath, cookieFileName)); e != nil {
+		t.Fatal(e)
+	}
+}
+
+// TestChallengeReason ...
+func TestChallengeReason(t *testing.T) {
+	if v := challengeReason([]byte(`<div class="g-recaptcha"></div>`), 200, ""); v != "" {
+		t.Fatal("a captcha form on a normal page is not a challenge")
+	}
+	if v := challengeReason([]byte(`<div class="g-recaptcha"></div>`), 403, ""); v != "captcha" {
+		t.Fatal(v)
+	}
+	if v := challengeReason([]byte(`<form id="challenge-form">`), 200, ""); v != "cloudflare" {
+		t.Fatal(v)
+	}
+}
+
+// TestAgeVerifyBlocked ...
+func TestAgeVerifyBlocked(t *testing.T) {
+	for _, test := range []struct {
+		location string
+		want     string
+	}{
+		{"https://www.javbus.com/doc/driver-verify?referer=/ABC-001", "age-verify"},
+		{"https://javdb.com/over18?respond=1", "age-verify"},
+		{"https://javdb7.com/v/abc", ""},
+		{"https://javdb.com/doc/driver-verify", ""},
+		{"https://www.javbus.com/over18", ""},
+		{"https://example.com/over18", ""},
+	} {
+		if v := challengeReason([]byte("<html></html>"), 200, test.location); v != test.want {
+			t.Fatal(test.location, v)
+		}
+	}
+	if v := challengeReason([]byte(`<div class="modal is-active over18-modal"></div>`), 200, "https://javdb.com/v/abc"); v != "" {
+		t.Fatal("the over 18 modal is not a challenge")
+	}
+	if v := siteCookies["javdb.com"]; len(v) != 1 || v[0].Name != "over18" {
+		t.Fatal(v)
+	}
+
+	testCache(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		//a page of a site that is not javbus is not blocked by the driver verification path
+		if !strings.HasPrefix(r.URL.Path, "/doc/driver-verify") {
+			http.Redirect(w, r, "/doc/driver-verify?referer="+r.URL.Path, http.StatusFound)
+			return
+		}
+		fmt.Fprint(w, `<html><body><div class="modal is-active over18-modal"></div></body></html>`)
+	}))
+	defer server.Close()
+	javdb := NewGrabJavdb()
+	javdb.MainPage(server.URL)
+	javdb.SetForce(true)
+	if _, e := javdb.Find("ABC-001"); e == nil || errors.Is(e, ErrBlocked) {
+		t.Fatal("javdb:", e)
+	}
+}
@@ -0,0 +1,269 @@
+package scrape
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MergePolicy how a new scraped content is applied to an existing one
+type MergePolicy int
+
+// MergePolicy detail ...
+const (
+	// PolicyFillEmpty only fill the empty fields of the existing content
+	PolicyFillEmpty MergePolicy = iota
+	// PolicyOverwrite replace the existing content
+	PolicyOverwrite
+	// PolicyKeepManual replace the fields not edited since they were written
+	PolicyKeepManual
+)
+
+var mergePolicyStringList = map[MergePolicy]string{
+	PolicyFillEmpty:  "fill-empty",
+	PolicyOverwrite:  "overwrite",
+	PolicyKeepManual: "keep-manual",
+}
+
+func (p MergePolicy) String() string {
+	return mergePolicyStringList[p]
+}
+
+// ParseMergePolicy ...
+func ParseMergePolicy(s string) (MergePolicy, error) {
+	for p, v := range mergePolicyStringList {
+		if v == s {
+			return p, nil
+		}
+	}
+	return PolicyFillEmpty, fmt.Errorf("unknown merge policy %s", s)
+}
+
+// FieldChange ...
+type FieldChange struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// ContentDiff ...
+type ContentDiff struct {
+	ID            string
+	Fields        []*FieldChange
+	ActorsAdded   []string
+	ActorsRemoved []string
+	GenresAdded   []string
+	GenresRemoved []string
+	SampleAdded   []string
+}
+
+type contentField struct {
+	name  string
+	value func(c *Content) string
+	copy  func(to, from *Content)
+}
+
+var contentFields = []contentField{
+	{"Title", func(c *Content) string { return c.Title }, func(to, from *Content) { to.Title = from.Title }},
+	{"OriginalTitle", func(c *Content) string { return c.OriginalTitle }, func(to, from *Content) { to.OriginalTitle = from.OriginalTitle }},
+	{"Year", func(c *Content) string { return c.Year }, func(to, from *Content) { to.Year = from.Year }},
+	{"ReleaseDate", diffDate, func(to, from *Content) { to.ReleaseDate = from.ReleaseDate }},
+	{"Studio", func(c *Content) string { return c.Studio }, func(to, from *Content) { to.Studio = from.Studio }},
+	{"Director", func(c *Content) string { return c.Director }, func(to, from *Content) { to.Director = from.Director }},
+	{"Runtime", func(c *Content) string { return diffRuntime(c.Runtime) }, func(to, from *Content) { to.Runtime = from.Runtime }},
+	{"MovieSet", func(c *Content) string { return c.MovieSet }, func(to, from *Content) { to.MovieSet = from.MovieSet }},
+	{"Publisher", func(c *Content) string { return c.Publisher }, func(to, from *Content) { to.Publisher = from.Publisher }},
+	{"Plot", func(c *Content) string { return c.Plot }, func(to, from *Content) { to.Plot, to.PlotFrom = from.Plot, from.PlotFrom }},
+	{"Rating", func(c *Content) string { return diffRating(c.Rating) }, func(to, from *Content) { to.Rating = from.Rating }},
+	{"Poster", func(c *Content) string { return c.Poster }, func(to, from *Content) { to.Poster = from.Poster }},
+	{"Thumb", func(c *Content) string { return c.Thumb }, func(to, from *Content) { to.Thumb = from.Thumb }},
+	{"Genres", func(c *Content) string { return strings.Join(diffGenres(c.Genres), ",") }, func(to, from *Content) { to.Genres = from.Genres }},
+	{"Actors", func(c *Content) string { return strings.Join(diffActors(c.Actors), ",") }, func(to, from *Content) { to.Actors = from.Actors }},
+	{"Sample", func(c *Content) string { return strings.Join(diffSample(c.Sample), ",") }, func(to, from *Content) { to.Sample = from.Sample }},
+}
+
+// DiffContent return the changed fields from old to new
+func DiffContent(old, new *Content) []*FieldChange {
+	var changes []*FieldChange
+	for _, f := range contentFields {
+		o, n := f.value(old), f.value(new)
+		if f.name == "Sample" {
+			o, n = strconv.Itoa(len(old.Sample)), strconv.Itoa(len(new.Sample))
+		}
+		if o != n {
+			changes = append(changes, &FieldChange{
+				Field: f.name,
+				Old:   o,
+				New:   n,
+			})
+		}
+	}
+	return changes
+}
+
+// Diff ...
+func Diff(old, new *Content) *ContentDiff {
+	d := &ContentDiff{
+		ID:     new.ID,
+		Fields: DiffContent(old, new),
+	}
+	d.ActorsAdded, d.ActorsRemoved = diffList(diffActors(old.Actors), diffActors(new.Actors))
+	d.GenresAdded, d.GenresRemoved = diffList(diffGenres(old.Genres), diffGenres(new.Genres))
+	d.SampleAdded, _ = diffList(diffSample(old.Sample), diffSample(new.Sample))
+	return d
+}
+
+// Empty ...
+func (d *ContentDiff) Empty() bool {
+	return len(d.Fields) == 0
+}
+
+// String the changelog of the diff
+func (d *ContentDiff) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %d fields changed\n", d.ID, len(d.Fields))
+	for _, c := range d.Fields {
+		if c.Field == "Genres" || c.Field == "Actors" || c.Field == "Sample" {
+			continue
+		}
+		fmt.Fprintf(&b, "\t%s: %q -> %q\n", c.Field, c.Old, c.New)
+	}
+	for _, list := range []struct {
+		name  string
+		value []string
+	}{
+		{"+actor", d.ActorsAdded},
+		{"-actor", d.ActorsRemoved},
+		{"+genre", d.GenresAdded},
+		{"-genre", d.GenresRemoved},
+		{"+sample", d.SampleAdded},
+	} {
+		for _, v := range list.value {
+			fmt.Fprintf(&b, "\t%s %s\n", list.name, v)
+		}
+	}
+	return b.String()
+}
+
+// ApplyPolicy return the content to write when new is scraped for the existing old,
+// the locked fields of old are always kept,
+// the checksums of the result are updated so manual edits can be found next time
+func ApplyPolicy(policy MergePolicy, old, new *Content) *Content {
+	result := *new
+	if policy == PolicyFillEmpty {
+		result = *old
+		if !new.Scraped.IsZero() {
+			result.Scraped = new.Scraped
+		}
+	}
+	checksums := make(map[string]string, len(contentFields))
+	for _, f := range contentFields {
+		if old.IsLocked(f.name) {
+			f.copy(&result, old)
+			if sum, b := old.Checksums[f.name]; b {
+				checksums[f.name] = sum
+			} else {
+				checksums[f.name] = fieldChecksum(f.value(old))
+			}
+			continue
+		}
+		switch policy {
+		case PolicyFillEmpty:
+			if f.value(old) == "" {
+				f.copy(&result, new)
+			}
+		case PolicyKeepManual:
+			if sum, b := old.Checksums[f.name]; b && sum != fieldChecksum(f.value(old)) {
+				f.copy(&result, old)
+				checksums[f.name] = sum
+				continue
+			}
+			//a value is never replaced with nothing
+			if f.value(new) == "" {
+				f.copy(&result, old)
+			}
+		}
+		checksums[f.name] = fieldChecksum(f.value(&result))
+	}
+	result.Checksums = checksums
+	result.Locked = old.Locked
+	return &result
+}
+
+// contentChecksums ...
+func contentChecksums(c *Content) map[string]string {
+	checksums := make(map[string]string, len(contentFields))
+	for _, f := range contentFields {
+		checksums[f.name] = fieldChecksum(f.value(c))
+	}
+	return checksums
+}
+
+func fieldChecksum(value string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(value)))[:16]
+}
+
+func diffList(old, new []string) (added, removed []string) {
+	exist := make(map[string]bool, len(old))
+	for _, v := range old {
+		exist[v] = true
+	}
+	for _, v := range new {
+		if !exist[v] {
+			added = append(added, v)
+		}
+		delete(exist, v)
+	}
+	for _, v := range old {
+		if exist[v] {
+			removed = append(removed, v)
+		}
+	}
+	return added, removed
+}
+
+func diffDate(c *Content) string {
+	if c.ReleaseDate.IsZero() {
+		return ""
+	}
+	return c.ReleaseDate.Format(javbusTimeFormat)
+}
+
+func diffRuntime(runtime int) string {
+	if runtime == 0 {
+		return ""
+	}
+	return strconv.Itoa(runtime)
+}
+
+func diffRating(rating float64) string {
+	if rating == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(rating, 'f', 2, 64)
+}
+
+func diffGenres(genres []*Genre) []string {
+	var list []string
+	for _, g := range genres {
+		list = append(list, g.Content)
+	}
+	return list
+}
+
+func diffActors(actors []*Star) []string {
+	var list []string
+	for _, a := range actors {
+		list = append(list, a.Name)
+	}
+	return list
+}
+
+func diffSample(sample []*Sample) []string {
+	var list []string
+	for _, s := range sample {
+		list = append(list, s.Image)
+	}
+	return list
+}
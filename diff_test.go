@@ -0,0 +1,68 @@
+package scrape
+
+import "testing"
+
+// TestApplyPolicy ...
+func TestApplyPolicy(t *testing.T) {
+	written := &Content{ID: "ABP-891", Title: "scraped", Studio: "old"}
+	written.Checksums = contentChecksums(written)
+	edited := *written
+	edited.Title = "edited"
+	scraped := &Content{ID: "ABP-891", Title: "new", Studio: "new", Plot: "plot"}
+
+	c := ApplyPolicy(PolicyKeepManual, &edited, scraped)
+	if c.Title != "edited" || c.Studio != "new" || c.Plot != "plot" {
+		t.Fatalf("keep manual: %+v", c)
+	}
+	c = ApplyPolicy(PolicyKeepManual, c, scraped)
+	if c.Title != "edited" {
+		t.Fatalf("keep manual again: %+v", c)
+	}
+	//the info files written before the checksums keep their values when the site has none
+	c = ApplyPolicy(PolicyKeepManual, &Content{ID: "ABP-891", Title: "old", Director: "old"}, &Content{ID: "ABP-891", Studio: "new"})
+	if c.Title != "old" || c.Director != "old" || c.Studio != "new" || c.Checksums["Title"] != fieldChecksum("old") {
+		t.Fatalf("keep manual without checksums: %+v", c)
+	}
+	c = ApplyPolicy(PolicyFillEmpty, &edited, scraped)
+	if c.Title != "edited" || c.Studio != "old" || c.Plot != "plot" {
+		t.Fatalf("fill empty: %+v", c)
+	}
+	c = ApplyPolicy(PolicyOverwrite, &edited, scraped)
+	if c.Title != "new" || c.Studio != "new" {
+		t.Fatalf("overwrite: %+v", c)
+	}
+}
+
+// TestDiff ...
+func TestDiff(t *testing.T) {
+	d := Diff(&Content{
+		ID:     "ABP-891",
+		Actors: []*Star{{Name: "a"}, {Name: "b"}},
+		Sample: []*Sample{{Image: "1"}},
+	}, &Content{
+		ID:     "ABP-891",
+		Actors: []*Star{{Name: "b"}, {Name: "c"}},
+		Genres: []*Genre{{Content: "g"}},
+		Sample: []*Sample{{Image: "1"}, {Image: "2"}},
+	})
+	if len(d.ActorsAdded) != 1 || d.ActorsAdded[0] != "c" || len(d.ActorsRemoved) != 1 || d.ActorsRemoved[0] != "a" {
+		t.Fatalf("actors: %+v", d)
+	}
+	if len(d.GenresAdded) != 1 || len(d.SampleAdded) != 1 || d.SampleAdded[0] != "2" || len(d.Fields) != 3 {
+		t.Fatalf("%s", d)
+	}
+}
+
+// TestApplyPolicyLocked ...
+func TestApplyPolicyLocked(t *testing.T) {
+	old := &Content{ID: "ABP-891", Title: "fixed", Plot: "fixed", Studio: "old", Locked: []string{"Title", "overview"}}
+	c := ApplyPolicy(PolicyOverwrite, old, &Content{ID: "ABP-891", Title: "new", Plot: "new", Studio: "new"})
+	if c.Title != "fixed" || c.Plot != "fixed" || c.Studio != "new" || len(c.Locked) != 2 {
+		t.Fatalf("%+v", c)
+	}
+	old.Locked = []string{"*"}
+	c = ApplyPolicy(PolicyOverwrite, old, &Content{ID: "ABP-891", Title: "new", Studio: "new"})
+	if c.Title != "fixed" || c.Studio != "old" {
+		t.Fatalf("%+v", c)
+	}
+}
@@ -4,8 +4,12 @@ go 1.13
 
 require (
 	github.com/PuerkitoBio/goquery v1.5.0
+	github.com/dgraph-io/badger/v3 v3.2011.1
+	github.com/fsnotify/fsnotify v1.4.9
 	github.com/gocacher/badger-cache/v3 v3.0.2
 	github.com/gocacher/cacher v1.0.5
 	github.com/goextension/log v0.0.2
 	golang.org/x/net v0.0.0-20201021035429-f5854403a974
+	golang.org/x/text v0.3.3
+	gopkg.in/yaml.v2 v2.2.2
 )
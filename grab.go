@@ -1,5 +1,11 @@
 package scrape
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
 // IGrab ...
 type IGrab interface {
 	MainPage(url string)
@@ -14,6 +20,52 @@ type IGrab interface {
 	SetForce(force bool)
 }
 
+// ICloneable a grab copied for every find,the copy keeps the options but not the results
+type ICloneable interface {
+	Clone() IGrab
+}
+
+// cloneGrab copy the grab when it is cloneable,the others are used as they are
+func cloneGrab(grab IGrab) IGrab {
+	if c, b := grab.(ICloneable); b {
+		return c.Clone()
+	}
+	return grab
+}
+
+// IBrowse list every id of an actor,studio,series or genre,
+// the argument is a link(absolute or relative to the main page) or a name,
+// when a page after the first one fails the ids of the pages before are returned with a *ListError
+type IBrowse interface {
+	ListByActor(actor string) ([]string, error)
+	ListByStudio(studio string) ([]string, error)
+	ListBySeries(series string) ([]string, error)
+	ListByGenre(genre string) ([]string, error)
+}
+
+// ErrLanguageNotSupported ...
+var ErrLanguageNotSupported = errors.New("language not supported")
+
+// ErrBlocked the site answers with an anti-bot challenge instead of the page
+var ErrBlocked = errors.New("blocked by anti-bot challenge")
+
+// ListError a page of the list failed after some pages were read
+type ListError struct {
+	URL  string
+	Page int
+	Err  error
+}
+
+// Error ...
+func (e *ListError) Error() string {
+	return fmt.Sprintf("list page %d(%s): %v", e.Page, e.URL, e.Err)
+}
+
+// Unwrap ...
+func (e *ListError) Unwrap() error {
+	return e.Err
+}
+
 // GrabLanguage ...
 type GrabLanguage int
 
@@ -41,3 +93,35 @@ func (g GrabLanguage) String() string {
 	}
 	return v
 }
+
+// browseLink return the absolute link if the value is a link
+func browseLink(mainPage, value string) (string, bool) {
+	switch {
+	case strings.HasPrefix(value, "http://"), strings.HasPrefix(value, "https://"):
+		return value, true
+	case strings.HasPrefix(value, "/"):
+		return mainPage + value, true
+	}
+	return "", false
+}
+
+// idSet keep the ids in the order they are added without duplicates
+type idSet struct {
+	ids  []string
+	seen map[string]bool
+}
+
+// add append the ids not in the set yet
+func (s *idSet) add(ids ...string) {
+	if s.seen == nil {
+		s.seen = make(map[string]bool)
+	}
+	for _, id := range ids {
+		id = strings.ToUpper(strings.TrimSpace(id))
+		if id == "" || s.seen[id] {
+			continue
+		}
+		s.seen[id] = true
+		s.ids = append(s.ids, id)
+	}
+}
@@ -66,11 +66,6 @@ func (g *grabBp4x) Result() ([]Content, error) {
 	panic("implement me")
 }
 
-// Clone ...
-func (g *grabBp4x) Clone() IGrab {
-	panic("implement me")
-}
-
 // HasNext ...
 func (g *grabBp4x) HasNext() bool {
 	panic("implement me")
@@ -0,0 +1,472 @@
+package scrape
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/goextension/log"
+	"gopkg.in/yaml.v2"
+)
+
+// GrabDefinition a grab described by a yaml/json file instead of code
+type GrabDefinition struct {
+	Name       string                  `yaml:"name" json:"name"`
+	MainPage   string                  `yaml:"main_page" json:"main_page"`
+	Search     string                  `yaml:"search" json:"search"`       //search url relative to the main page,%s is the id
+	Languages  map[string]string       `yaml:"languages" json:"languages"` //language name to the path inserted before the search url
+	Result     ResultDefinition        `yaml:"result" json:"result"`
+	Detail     DetailDefinition        `yaml:"detail" json:"detail"`
+	Next       FieldDefinition         `yaml:"next" json:"next"` //link of the next search page
+	Labels     map[string]LabelStrings `yaml:"labels" json:"labels"`
+	Capability GrabCapability          `yaml:"capability" json:"capability"`
+}
+
+// LabelStrings the labels of a detail field,key is the language name
+type LabelStrings map[string][]string
+
+// FieldDefinition select a value,an empty selector is the current element,an empty attr is the text
+type FieldDefinition struct {
+	Selector  string   `yaml:"selector" json:"selector"`
+	Attr      string   `yaml:"attr" json:"attr"`
+	Transform []string `yaml:"transform" json:"transform"`
+}
+
+// ResultDefinition ...
+type ResultDefinition struct {
+	Item  string          `yaml:"item" json:"item"`
+	Link  FieldDefinition `yaml:"link" json:"link"`
+	ID    FieldDefinition `yaml:"id" json:"id"`
+	Title FieldDefinition `yaml:"title" json:"title"`
+	Thumb FieldDefinition `yaml:"thumb" json:"thumb"`
+}
+
+// ListDefinition select a list,the fields are relative to every item
+type ListDefinition struct {
+	Item  string          `yaml:"item" json:"item"`
+	Name  FieldDefinition `yaml:"name" json:"name"`
+	Link  FieldDefinition `yaml:"link" json:"link"`
+	Image FieldDefinition `yaml:"image" json:"image"`
+	Thumb FieldDefinition `yaml:"thumb" json:"thumb"`
+}
+
+// DetailDefinition ...
+type DetailDefinition struct {
+	Title      FieldDefinition `yaml:"title" json:"title"`
+	Poster     FieldDefinition `yaml:"poster" json:"poster"`
+	Plot       FieldDefinition `yaml:"plot" json:"plot"`
+	Uncensored FieldDefinition `yaml:"uncensored" json:"uncensored"` //uncensored when the value is not empty
+	Row        string          `yaml:"row" json:"row"`               //the label rows
+	Label      FieldDefinition `yaml:"label" json:"label"`           //relative to the row
+	Value      FieldDefinition `yaml:"value" json:"value"`           //relative to the row
+	DateFormat string          `yaml:"date_format" json:"date_format"`
+	Genres     ListDefinition  `yaml:"genres" json:"genres"`
+	Actors     ListDefinition  `yaml:"actors" json:"actors"`
+	Sample     ListDefinition  `yaml:"sample" json:"sample"`
+}
+
+// the label fields of DetailDefinition
+const (
+	definitionID          = "id"
+	definitionReleaseDate = "release_date"
+	definitionLength      = "length"
+	definitionDirector    = "director"
+	definitionStudio      = "studio"
+	definitionPublisher   = "publisher"
+	definitionSeries      = "series"
+)
+
+// LoadGrabDefinition ...
+func LoadGrabDefinition(path string) (*GrabDefinition, error) {
+	bys, e := ioutil.ReadFile(path)
+	if e != nil {
+		return nil, e
+	}
+	return ParseGrabDefinition(bys)
+}
+
+// ParseGrabDefinition parse a yaml or json definition
+func ParseGrabDefinition(bys []byte) (*GrabDefinition, error) {
+	def := new(GrabDefinition)
+	e := yaml.Unmarshal(bys, def)
+	if e != nil {
+		return nil, e
+	}
+	switch {
+	case def.Name == "":
+		return nil, errors.New("definition name is empty")
+	case def.MainPage == "":
+		return nil, errors.New("definition main_page is empty")
+	case def.Search == "":
+		return nil, errors.New("definition search is empty")
+	case def.Result.Item == "":
+		return nil, errors.New("definition result.item is empty")
+	}
+	if def.Detail.DateFormat == "" {
+		def.Detail.DateFormat = javbusTimeFormat
+	}
+	detail, result := def.Detail, def.Result
+	fields := []FieldDefinition{def.Next, result.Link, result.ID, result.Title, result.Thumb,
+		detail.Title, detail.Poster, detail.Plot, detail.Uncensored, detail.Label, detail.Value}
+	for _, list := range []ListDefinition{detail.Genres, detail.Actors, detail.Sample} {
+		fields = append(fields, list.Name, list.Link, list.Image, list.Thumb)
+	}
+	for _, f := range fields {
+		for _, t := range f.Transform {
+			if _, e := definitionTransform(t); e != nil {
+				return nil, e
+			}
+		}
+	}
+	return def, nil
+}
+
+type grabDefinition struct {
+	def      *GrabDefinition
+	mainPage string
+	language GrabLanguage
+	next     string
+	sample   bool
+	exact    bool
+	finder   string
+	details  []*Content
+	cache    *Cache
+	force    bool
+}
+
+// NewGrabFromDefinition ...
+func NewGrabFromDefinition(def *GrabDefinition) IGrab {
+	return &grabDefinition{
+		def:      def,
+		mainPage: strings.TrimSuffix(def.MainPage, "/"),
+		language: LanguageChineseTraditional,
+		exact:    true,
+		cache:    NewCache(),
+	}
+}
+
+// MainPage ...
+func (g *grabDefinition) MainPage(url string) {
+	g.mainPage = strings.TrimSuffix(url, "/")
+}
+
+// SetSample ...
+func (g *grabDefinition) SetSample(b bool) {
+	g.sample = b
+}
+
+// SetExact ...
+func (g *grabDefinition) SetExact(b bool) {
+	g.exact = b
+}
+
+// SetLanguage ...
+func (g *grabDefinition) SetLanguage(language GrabLanguage) {
+	g.language = language
+}
+
+// SetForce ...
+func (g *grabDefinition) SetForce(force bool) {
+	g.force = force
+}
+
+// Name ...
+func (g *grabDefinition) Name() string {
+	return g.def.Name
+}
+
+// HasNext ...
+func (g *grabDefinition) HasNext() bool {
+	return g.next != ""
+}
+
+// Next ...
+func (g *grabDefinition) Next() (IGrab, error) {
+	return g.find(g.next)
+}
+
+// Result ...
+func (g *grabDefinition) Result() ([]Content, error) {
+	var cs []Content
+	for _, c := range g.details {
+		cs = append(cs, *c)
+	}
+	return cs, nil
+}
+
+// Find ...
+func (g *grabDefinition) Find(name string) (IGrab, error) {
+	g.finder = name
+	prefix := ""
+	if len(g.def.Languages) != 0 {
+		var b bool
+		prefix, b = g.def.Languages[g.language.String()]
+		if !b {
+			return g, fmt.Errorf("%s %s: %w", g.Name(), g.language, ErrLanguageNotSupported)
+		}
+	}
+	return g.find(g.mainPage + prefix + fmt.Sprintf(g.def.Search, name))
+}
+
+func (g *grabDefinition) clone() *grabDefinition {
+	clone := new(grabDefinition)
+	*clone = *g
+	clone.details = nil
+	return clone
+}
+
+// Clone ...
+func (g *grabDefinition) Clone() IGrab {
+	return g.clone()
+}
+
+func (g *grabDefinition) find(url string) (IGrab, error) {
+	clone := g.clone()
+	document, e := clone.cache.Query(url, g.force)
+	if e != nil {
+		return clone, e
+	}
+	clone.next = ""
+	if next := clone.field(document.Selection, g.def.Next); next != "" && g.def.Next.Selector != "" {
+		clone.next = clone.absolute(next)
+	}
+
+	type result struct {
+		link, id, title, thumb string
+	}
+	var results []*result
+	document.Find(g.def.Result.Item).Each(func(i int, selection *goquery.Selection) {
+		r := &result{
+			link:  clone.absolute(clone.field(selection, g.def.Result.Link)),
+			id:    clone.field(selection, g.def.Result.ID),
+			title: clone.field(selection, g.def.Result.Title),
+			thumb: clone.absolute(clone.field(selection, g.def.Result.Thumb)),
+		}
+		if r.link != "" {
+			results = append(results, r)
+		}
+	})
+	if len(results) == 0 {
+		return clone, errors.New("no data found")
+	}
+
+	for _, r := range results {
+		if clone.exact && r.id != "" && !strings.EqualFold(r.id, clone.finder) {
+			continue
+		}
+		content, e := clone.detail(r.link)
+		if e != nil {
+			log.Errorw("definition", "name", g.Name(), "link", r.link, "error", e)
+			continue
+		}
+		if content.ID == "" {
+			content.ID = strings.ToUpper(r.id)
+		}
+		if clone.exact && !strings.EqualFold(content.ID, clone.finder) {
+			continue
+		}
+		if content.Title == "" {
+			content.Title = r.title
+		}
+		content.Thumb = r.thumb
+		clone.details = append(clone.details, content)
+		if clone.exact {
+			break
+		}
+	}
+	return clone, nil
+}
+
+func (g *grabDefinition) detail(url string) (*Content, error) {
+	document, e := g.cache.Query(url, g.force)
+	if e != nil {
+		return nil, e
+	}
+	def := g.def.Detail
+	content := &Content{
+		From:     g.Name(),
+		Language: g.language.String(),
+		Title:    g.field(document.Selection, def.Title),
+		Poster:   g.absolute(g.field(document.Selection, def.Poster)),
+		Plot:     g.field(document.Selection, def.Plot),
+	}
+	if content.Plot != "" {
+		content.PlotFrom = g.Name()
+	}
+	if def.Uncensored.Selector != "" {
+		content.Uncensored = g.field(document.Selection, def.Uncensored) != ""
+	}
+	if def.Row != "" {
+		document.Find(def.Row).Each(func(i int, selection *goquery.Selection) {
+			label := g.field(selection, def.Label)
+			value := g.field(selection, def.Value)
+			switch g.label(label) {
+			case definitionID:
+				content.ID = strings.ToUpper(value)
+			case definitionReleaseDate:
+				content.ReleaseDate, e = time.Parse(def.DateFormat, value)
+				if e != nil {
+					log.Warnw("definition", "name", g.Name(), "date", value, "error", e)
+				}
+				if !content.ReleaseDate.IsZero() {
+					content.Year = strconv.Itoa(content.ReleaseDate.Year())
+				}
+			case definitionDirector:
+				content.Director = value
+			case definitionStudio:
+				content.Studio = value
+			case definitionPublisher:
+				content.Publisher = value
+			case definitionSeries:
+				content.MovieSet = value
+			case definitionLength:
+				content.Runtime = parseRuntime(value)
+			default:
+				if debug {
+					log.Infow("definition", "name", g.Name(), "label", label, "value", value)
+				}
+			}
+		})
+	}
+	g.list(document, def.Genres, func(i int, name, link, image, thumb string) {
+		content.Genres = append(content.Genres, &Genre{Content: name, URL: link})
+	})
+	g.list(document, def.Actors, func(i int, name, link, image, thumb string) {
+		content.Actors = append(content.Actors, &Star{Name: name, Link: link, Image: image})
+	})
+	if g.sample {
+		g.list(document, def.Sample, func(i int, name, link, image, thumb string) {
+			content.Sample = append(content.Sample, &Sample{Index: i, Title: name, Image: image, Thumb: thumb})
+		})
+	}
+	return content, nil
+}
+
+// label return the detail field of the label in the current language,
+// the longest matched label wins so 發行日期 is not taken as 發行
+func (g *grabDefinition) label(label string) string {
+	if label == "" {
+		return ""
+	}
+	found, size := "", 0
+	for field, languages := range g.def.Labels {
+		for _, v := range languages[g.language.String()] {
+			if v == "" || !strings.Contains(label, v) {
+				continue
+			}
+			//the same size is decided by the field name,the map order is random
+			if len(v) > size || (len(v) == size && field < found) {
+				found, size = field, len(v)
+			}
+		}
+	}
+	return found
+}
+
+func (g *grabDefinition) list(document *goquery.Document, def ListDefinition, f func(i int, name, link, image, thumb string)) {
+	if def.Item == "" {
+		return
+	}
+	document.Find(def.Item).Each(func(i int, selection *goquery.Selection) {
+		f(i,
+			g.field(selection, def.Name),
+			g.absolute(g.field(selection, def.Link)),
+			g.absolute(g.field(selection, def.Image)),
+			g.absolute(g.field(selection, def.Thumb)),
+		)
+	})
+}
+
+// field select the value and apply the transforms
+func (g *grabDefinition) field(selection *goquery.Selection, def FieldDefinition) string {
+	if def.Selector == "" && def.Attr == "" && len(def.Transform) == 0 {
+		return ""
+	}
+	if def.Selector != "" {
+		selection = selection.Find(def.Selector).First()
+	}
+	value := ""
+	if def.Attr != "" {
+		value = selection.AttrOr(def.Attr, "")
+	} else {
+		value = selection.Text()
+	}
+	for _, t := range def.Transform {
+		f, e := definitionTransform(t)
+		if e != nil {
+			log.Errorw("definition", "name", g.Name(), "transform", t, "error", e)
+			continue
+		}
+		value = f(g, value)
+	}
+	return value
+}
+
+// absolute fix the relative links
+func (g *grabDefinition) absolute(link string) string {
+	switch {
+	case link == "":
+		return ""
+	case strings.HasPrefix(link, "//"):
+		return "https:" + link
+	case strings.HasPrefix(link, "/"):
+		return g.mainPage + link
+	}
+	return link
+}
+
+type definitionTransformFunc func(g *grabDefinition, value string) string
+
+// definitionTransform parse a transform:
+// trim,upper,lower,absolute,replace:old:new,trimprefix:s,trimsuffix:s,regexp:expr(the first group or the match)
+func definitionTransform(t string) (definitionTransformFunc, error) {
+	args := strings.SplitN(t, ":", 2)
+	switch args[0] {
+	case "trim":
+		return func(g *grabDefinition, value string) string { return strings.TrimSpace(value) }, nil
+	case "upper":
+		return func(g *grabDefinition, value string) string { return strings.ToUpper(value) }, nil
+	case "lower":
+		return func(g *grabDefinition, value string) string { return strings.ToLower(value) }, nil
+	case "absolute":
+		return func(g *grabDefinition, value string) string { return g.absolute(value) }, nil
+	}
+	if len(args) != 2 {
+		return nil, fmt.Errorf("unknown transform %s", t)
+	}
+	switch args[0] {
+	case "replace":
+		replace := strings.SplitN(args[1], ":", 2)
+		if len(replace) != 2 {
+			return nil, fmt.Errorf("wrong replace transform %s", t)
+		}
+		return func(g *grabDefinition, value string) string {
+			return strings.Replace(value, replace[0], replace[1], -1)
+		}, nil
+	case "trimprefix":
+		return func(g *grabDefinition, value string) string { return strings.TrimPrefix(value, args[1]) }, nil
+	case "trimsuffix":
+		return func(g *grabDefinition, value string) string { return strings.TrimSuffix(value, args[1]) }, nil
+	case "regexp":
+		r, e := regexp.Compile(args[1])
+		if e != nil {
+			return nil, e
+		}
+		return func(g *grabDefinition, value string) string {
+			match := r.FindStringSubmatch(value)
+			switch len(match) {
+			case 0:
+				return ""
+			case 1:
+				return match[0]
+			}
+			return match[1]
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown transform %s", t)
+}
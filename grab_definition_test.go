@@ -0,0 +1,171 @@
+package scrape
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const testDefinition = `
+name: test
+main_page: http://localhost
+search: /search/%s
+languages:
+  english: /en
+labels:
+  id:
+    english: ["ID:"]
+  release_date:
+    english: ["Release Date:"]
+  studio:
+    english: ["Studio:"]
+result:
+  item: div.item
+  link: {selector: a, attr: href}
+  id: {selector: span.id, transform: [trim, upper]}
+  thumb: {selector: img, attr: src}
+next: {selector: a.next, attr: href}
+detail:
+  title: {selector: h3, transform: [trim, "trimprefix:ABP-891 "]}
+  poster: {selector: a.poster, attr: href}
+  row: div.info p
+  label: {selector: span.header, transform: [trim]}
+  value: {selector: span.value, transform: [trim]}
+  genres:
+    item: span.genre a
+    name: {transform: [trim]}
+    link: {attr: href}
+  actors:
+    item: div.star
+    name: {selector: a, transform: [trim]}
+    image: {selector: img, attr: src, transform: ["replace:thumb:cover"]}
+  sample:
+    item: a.sample
+    image: {attr: href}
+    thumb: {selector: img, attr: src}
+`
+
+const testDefinitionSearch = `<html><body>
+<div class="item"><a href="/en/ABP-891"><img src="//img.test/thumb.jpg"></a><span class="id"> abp-891 </span></div>
+<div class="item"><a href="/en/ABP-892"><img src="//img.test/thumb2.jpg"></a><span class="id">abp-892</span></div>
+<a class="next" href="/en/search/abp/2">next</a>
+</body></html>`
+
+const testDefinitionDetail = `<html><body>
+<h3> ABP-891 The Title </h3>
+<a class="poster" href="/cover/abp891.jpg"></a>
+<div class="info">
+<p><span class="header">ID:</span><span class="value"> abp-891 </span></p>
+<p><span class="header">Release Date:</span><span class="value">2019-09-20</span></p>
+<p><span class="header">Studio:</span><span class="value">Prestige</span></p>
+<p><span class="header">Unknown:</span><span class="value">value</span></p>
+</div>
+<span class="genre"><a href="/genre/1"> Drama </a></span>
+<span class="genre"><a href="/genre/2">Solo</a></span>
+<div class="star"><a>Actor</a><img src="/thumb/actor.jpg"></div>
+<a class="sample" href="/sample/1.jpg"><img src="/sample/1s.jpg"></a>
+</body></html>`
+
+// TestGrabFromDefinition ...
+func TestGrabFromDefinition(t *testing.T) {
+	testCache(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/en/search/abp-891":
+			fmt.Fprint(w, testDefinitionSearch)
+		case "/en/ABP-891":
+			fmt.Fprint(w, testDefinitionDetail)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	def, e := ParseGrabDefinition([]byte(testDefinition))
+	if e != nil {
+		t.Fatal(e)
+	}
+	grab := NewGrabFromDefinition(def)
+	grab.MainPage(server.URL)
+	grab.SetLanguage(LanguageEnglish)
+	grab.SetSample(true)
+	grab.SetForce(true)
+	if grab.Name() != "test" {
+		t.Fatal(grab.Name())
+	}
+	find, e := grab.Find("abp-891")
+	if e != nil {
+		t.Fatal(e)
+	}
+	if !find.HasNext() {
+		t.Fatal("next page is lost")
+	}
+	contents, e := find.Result()
+	if e != nil || len(contents) != 1 {
+		t.Fatal(contents, e)
+	}
+	c := contents[0]
+	if c.ID != "ABP-891" || c.Title != "The Title" || c.Studio != "Prestige" || c.Year != "2019" {
+		t.Fatalf("%+v", c)
+	}
+	if c.Poster != server.URL+"/cover/abp891.jpg" || c.Thumb != "https://img.test/thumb.jpg" {
+		t.Fatal(c.Poster, c.Thumb)
+	}
+	if len(c.Genres) != 2 || c.Genres[0].Content != "Drama" || c.Genres[0].URL != server.URL+"/genre/1" {
+		t.Fatalf("%+v", c.Genres)
+	}
+	if len(c.Actors) != 1 || c.Actors[0].Name != "Actor" || c.Actors[0].Image != server.URL+"/cover/actor.jpg" {
+		t.Fatalf("%+v", c.Actors)
+	}
+	if len(c.Sample) != 1 || c.Sample[0].Thumb != server.URL+"/sample/1s.jpg" {
+		t.Fatalf("%+v", c.Sample)
+	}
+
+	grab.SetLanguage(LanguageJapanese)
+	if _, e := grab.Find("abp-891"); e == nil {
+		t.Fatal("japanese is not in the definition")
+	}
+}
+
+// TestParseGrabDefinition ...
+func TestParseGrabDefinition(t *testing.T) {
+	if _, e := ParseGrabDefinition([]byte(`{"name":"json","main_page":"http://localhost","search":"/%s","result":{"item":"a"}}`)); e != nil {
+		t.Fatal(e)
+	}
+	if _, e := ParseGrabDefinition([]byte("name: test")); e == nil {
+		t.Fatal("main_page is required")
+	}
+	if _, e := ParseGrabDefinition([]byte(testDefinition + "\n  plot: {transform: [unknown]}\n")); e == nil {
+		t.Fatal("unknown transform")
+	}
+}
+
+// TestDefinitionLabel ...
+func TestDefinitionLabel(t *testing.T) {
+	grab := &grabDefinition{
+		def: &GrabDefinition{Labels: map[string]LabelStrings{
+			definitionPublisher:   {"traditional chinese": {"發行商"}, "english": {"Label"}},
+			definitionReleaseDate: {"traditional chinese": {"發行日期"}, "english": {"Release Date"}},
+			definitionStudio:      {"traditional chinese": {"發行"}, "english": {"Release"}},
+		}},
+		language: LanguageChineseTraditional,
+	}
+	//the map order changes between the runs
+	for i := 0; i < 50; i++ {
+		for label, field := range map[string]string{
+			"發行日期:": definitionReleaseDate,
+			"發行商:":  definitionPublisher,
+			"發行:":   definitionStudio,
+			"長度:":   "",
+		} {
+			if v := grab.label(label); v != field {
+				t.Fatalf("label %s: got %s want %s", label, v, field)
+			}
+		}
+	}
+	grab.language = LanguageEnglish
+	if v := grab.label("Release Date:"); v != definitionReleaseDate {
+		t.Fatal(v)
+	}
+}
@@ -0,0 +1,320 @@
+package scrape
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/goextension/log"
+)
+
+const dmmSearch = "/search/=/searchstr=%s/"
+const dmmTimeFormat = "2006/01/02"
+
+var dmmCIDRegexp = regexp.MustCompile(`^(?:\d+)?([a-z]+)(\d+)[a-z]?$`)
+var dmmLinkCIDRegexp = regexp.MustCompile(`cid=([a-z0-9_]+)`)
+var dmmSampleRegexp = regexp.MustCompile(`-(\d+)\.jpg$`)
+
+// dmmID convert a dmm content id like abp00891 or 118abp00891 to the id ABP-891
+func dmmID(cid string) string {
+	match := dmmCIDRegexp.FindStringSubmatch(strings.ToLower(strings.TrimSpace(cid)))
+	if match == nil {
+		return strings.ToUpper(cid)
+	}
+	number := strings.TrimLeft(match[2], "0")
+	for len(number) < 3 {
+		number = "0" + number
+	}
+	return strings.ToUpper(match[1]) + "-" + number
+}
+
+// dmmMonoContentID the content id of the dvd pages is not padded,like abp891
+func dmmMonoContentID(id string) string {
+	return strings.ToLower(strings.Replace(dmmID(dmmContentID(id)), "-", "", -1))
+}
+
+type grabDmm struct {
+	mainPage string
+	language GrabLanguage
+	sample   bool
+	exact    bool
+	finder   string
+	details  []*Content
+	cache    *Cache
+	force    bool
+}
+
+// MainPage ...
+func (g *grabDmm) MainPage(url string) {
+	g.mainPage = url
+}
+
+// SetSample ...
+func (g *grabDmm) SetSample(b bool) {
+	g.sample = b
+}
+
+// SetExact ...
+func (g *grabDmm) SetExact(b bool) {
+	g.exact = b
+}
+
+// SetLanguage ...
+func (g *grabDmm) SetLanguage(language GrabLanguage) {
+	g.language = language
+}
+
+// SetForce ...
+func (g *grabDmm) SetForce(force bool) {
+	g.force = force
+}
+
+// Name ...
+func (g *grabDmm) Name() string {
+	return "dmm"
+}
+
+// HasNext ...
+func (g *grabDmm) HasNext() bool {
+	return false
+}
+
+// Next ...
+func (g *grabDmm) Next() (IGrab, error) {
+	return nil, errors.New("dmm has no next page")
+}
+
+// Result ...
+func (g *grabDmm) Result() ([]Content, error) {
+	var cs []Content
+	for _, c := range g.details {
+		cs = append(cs, *c)
+	}
+	return cs, nil
+}
+
+func (g *grabDmm) clone() *grabDmm {
+	clone := new(grabDmm)
+	*clone = *g
+	clone.details = nil
+	return clone
+}
+
+// Clone ...
+func (g *grabDmm) Clone() IGrab {
+	return g.clone()
+}
+
+// Find ...
+func (g *grabDmm) Find(name string) (IGrab, error) {
+	if g.language != LanguageJapanese {
+		return g, fmt.Errorf("dmm %s: %w", g.language, ErrLanguageNotSupported)
+	}
+	clone := g.clone()
+	clone.finder = strings.ToUpper(name)
+	links := []string{
+		clone.mainPage + fmt.Sprintf(dmmDigitalDetail, dmmContentID(name)),
+		clone.mainPage + fmt.Sprintf(dmmMonoDetail, dmmMonoContentID(name)),
+	}
+	for _, link := range links {
+		content, e := clone.detail(link)
+		if e != nil {
+			log.Warnw("dmm", "link", link, "error", e)
+			continue
+		}
+		clone.details = append(clone.details, content)
+		return clone, nil
+	}
+	links, e := clone.search(name)
+	if e != nil {
+		return clone, e
+	}
+	for _, link := range links {
+		content, e := clone.detail(link)
+		if e != nil {
+			log.Warnw("dmm", "link", link, "error", e)
+			continue
+		}
+		clone.details = append(clone.details, content)
+		if clone.exact {
+			break
+		}
+	}
+	if len(clone.details) == 0 {
+		return clone, errors.New("no data found")
+	}
+	return clone, nil
+}
+
+// search return the detail links of the search result,only the same id in exact mode
+func (g *grabDmm) search(name string) ([]string, error) {
+	document, e := g.query(g.mainPage + fmt.Sprintf(dmmSearch, dmmContentID(name)))
+	if e != nil {
+		return nil, e
+	}
+	var links []string
+	document.Find("#list li p.tmb a").Each(func(i int, selection *goquery.Selection) {
+		link := selection.AttrOr("href", "")
+		match := dmmLinkCIDRegexp.FindStringSubmatch(link)
+		if match == nil {
+			return
+		}
+		if g.exact && dmmID(match[1]) != dmmID(dmmContentID(name)) {
+			return
+		}
+		if l, b := browseLink(g.mainPage, link); b {
+			links = append(links, l)
+		}
+	})
+	if len(links) == 0 {
+		return nil, errors.New("no data found")
+	}
+	return links, nil
+}
+
+// query get the page and check it is not the age check or the region block page
+func (g *grabDmm) query(url string) (*goquery.Document, error) {
+	document, e := g.cache.Query(url, g.force)
+	if e != nil {
+		return nil, e
+	}
+	switch {
+	case document.Find("a[href*='declared=yes']").Length() > 0:
+		g.cache.Delete(url)
+		return nil, errors.New("dmm age check page")
+	case strings.Contains(document.Find("title").Text(), "not available") ||
+		document.Find("div#foreignError").Length() > 0:
+		g.cache.Delete(url)
+		return nil, errors.New("dmm is not available in this region")
+	}
+	return document, nil
+}
+
+func (g *grabDmm) detail(url string) (*Content, error) {
+	document, e := g.query(url)
+	if e != nil {
+		return nil, e
+	}
+	title := strings.TrimSpace(document.Find("h1#title").Text())
+	if title == "" {
+		return nil, errors.New("no data found")
+	}
+	content := &Content{
+		From:          g.Name(),
+		Language:      g.language.String(),
+		Title:         title,
+		OriginalTitle: title,
+		Poster:        document.Find("#sample-video a[name='package-image']").AttrOr("href", ""),
+		Thumb:         document.Find("#sample-video img").First().AttrOr("src", ""),
+		Plot:          strings.TrimSpace(document.Find("div.mg-b20.lh4").First().Contents().First().Text()),
+	}
+	if content.Plot == "" {
+		content.Plot = strings.TrimSpace(document.Find("meta[property='og:description']").AttrOr("content", ""))
+	}
+	if content.Plot != "" {
+		content.PlotFrom = g.Name()
+	}
+	if match := dmmLinkCIDRegexp.FindStringSubmatch(url); match != nil {
+		content.ID = dmmID(match[1])
+	}
+	document.Find("table.mg-b20 tr").Each(func(i int, selection *goquery.Selection) {
+		tds := selection.Find("td")
+		if tds.Length() < 2 {
+			return
+		}
+		label := strings.TrimSpace(tds.First().Text())
+		value := tds.Eq(1)
+		text := strings.TrimSpace(value.Text())
+		switch {
+		case strings.HasPrefix(label, "配信開始日"), strings.HasPrefix(label, "商品発売日"), strings.HasPrefix(label, "発売日"):
+			if !content.ReleaseDate.IsZero() {
+				return
+			}
+			date, e := time.Parse(dmmTimeFormat, text)
+			if e != nil {
+				log.Warnw("dmm", "date", text, "error", e)
+				return
+			}
+			content.ReleaseDate = date
+			content.Year = strconv.Itoa(date.Year())
+		case strings.HasPrefix(label, "収録時間"):
+			content.Runtime = parseRuntime(text)
+		case strings.HasPrefix(label, "出演者"):
+			value.Find("a").Each(func(i int, selection *goquery.Selection) {
+				link, _ := browseLink(g.mainPage, selection.AttrOr("href", ""))
+				content.Actors = append(content.Actors, &Star{
+					Name: strings.TrimSpace(selection.Text()),
+					Link: link,
+				})
+			})
+		case strings.HasPrefix(label, "監督"):
+			content.Director = dmmValue(text)
+		case strings.HasPrefix(label, "シリーズ"):
+			content.MovieSet = dmmValue(text)
+		case strings.HasPrefix(label, "メーカー"):
+			content.Studio = dmmValue(text)
+		case strings.HasPrefix(label, "レーベル"):
+			content.Publisher = dmmValue(text)
+		case strings.HasPrefix(label, "ジャンル"):
+			value.Find("a").Each(func(i int, selection *goquery.Selection) {
+				link, _ := browseLink(g.mainPage, selection.AttrOr("href", ""))
+				content.Genres = append(content.Genres, &Genre{
+					Content: strings.TrimSpace(selection.Text()),
+					URL:     link,
+				})
+			})
+		case strings.HasPrefix(label, "品番"):
+			content.ID = dmmID(text)
+		}
+	})
+	if g.exact && g.finder != "" && content.ID != dmmID(dmmContentID(g.finder)) {
+		return nil, fmt.Errorf("dmm id %s is not %s", content.ID, g.finder)
+	}
+	if g.sample {
+		document.Find("#sample-image-block a img").Each(func(i int, selection *goquery.Selection) {
+			thumb := selection.AttrOr("src", "")
+			content.Sample = append(content.Sample, &Sample{
+				Index: i,
+				Thumb: thumb,
+				Image: dmmSampleRegexp.ReplaceAllString(thumb, "jp-$1.jpg"),
+			})
+		})
+	}
+	return content, nil
+}
+
+// dmmValue the empty values of dmm are shown as ----
+func dmmValue(value string) string {
+	if strings.Trim(value, "-") == "" {
+		return ""
+	}
+	return value
+}
+
+// GrabDmmOptions ...
+type GrabDmmOptions func(dmm *grabDmm)
+
+// DmmExact ...
+func DmmExact(b bool) GrabDmmOptions {
+	return func(dmm *grabDmm) {
+		dmm.exact = b
+	}
+}
+
+// NewGrabDmm ...
+func NewGrabDmm(ops ...GrabDmmOptions) IGrab {
+	grab := &grabDmm{
+		mainPage: DefaultDmmMainPage,
+		language: LanguageJapanese,
+		exact:    true,
+		cache:    NewCache(),
+	}
+	for _, op := range ops {
+		op(grab)
+	}
+	return grab
+}
@@ -0,0 +1,87 @@
+package scrape
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const testDmmDetail = `<html><head><title>ABP-891</title></head><body>
+<h1 id="title">The Title</h1>
+<div id="sample-video"><a name="package-image" href="https://pics.test/abp00891pl.jpg"><img src="https://pics.test/abp00891ps.jpg"></a></div>
+<table class="mg-b20">
+<tr><td>配信開始日：</td><td>2019/09/20</td></tr>
+<tr><td>収録時間：</td><td>120分</td></tr>
+<tr><td>出演者：</td><td><span id="performer"><a href="/digital/videoa/-/list/=/article=actress/id=1/">Actor</a></span></td></tr>
+<tr><td>監督：</td><td>----</td></tr>
+<tr><td>シリーズ：</td><td>Series</td></tr>
+<tr><td>メーカー：</td><td>プレステージ</td></tr>
+<tr><td>レーベル：</td><td>ABSOLUTELY PERFECT</td></tr>
+<tr><td>ジャンル：</td><td><a href="/genre/1">単体作品</a> <a href="/genre/2">美少女</a></td></tr>
+<tr><td>品番：</td><td>118abp00891</td></tr>
+</table>
+<div class="mg-b20 lh4">The plot.<p class="mg-b20"></p></div>
+<div id="sample-image-block"><a><img src="https://pics.test/abp00891-1.jpg"></a><a><img src="https://pics.test/abp00891-2.jpg"></a></div>
+</body></html>`
+
+// TestDmmID ...
+func TestDmmID(t *testing.T) {
+	for cid, id := range map[string]string{
+		"abp00891":    "ABP-891",
+		"118abp00891": "ABP-891",
+		"ssni00012":   "SSNI-012",
+		"h_068mxgs1":  "H_068MXGS1",
+	} {
+		if v := dmmID(cid); v != id {
+			t.Errorf("cid %s: got %s want %s", cid, v, id)
+		}
+	}
+	if v := dmmMonoContentID("ABP-891"); v != "abp891" {
+		t.Error(v)
+	}
+}
+
+// TestGrabDmm ...
+func TestGrabDmm(t *testing.T) {
+	testCache(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == fmt.Sprintf(dmmDigitalDetail, "abp00891") {
+			fmt.Fprint(w, testDmmDetail)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	grab := NewGrabDmm()
+	grab.MainPage(server.URL)
+	grab.SetSample(true)
+	grab.SetForce(true)
+	find, e := grab.Find("abp-891")
+	if e != nil {
+		t.Fatal(e)
+	}
+	contents, e := find.Result()
+	if e != nil || len(contents) != 1 {
+		t.Fatal(contents, e)
+	}
+	c := contents[0]
+	if c.ID != "ABP-891" || c.Title != "The Title" || c.Runtime != 120 || c.Year != "2019" || c.Plot != "The plot." {
+		t.Fatalf("%+v", c)
+	}
+	if c.Studio != "プレステージ" || c.Publisher != "ABSOLUTELY PERFECT" || c.MovieSet != "Series" || c.Director != "" {
+		t.Fatalf("%+v", c)
+	}
+	if len(c.Actors) != 1 || c.Actors[0].Link != server.URL+"/digital/videoa/-/list/=/article=actress/id=1/" || len(c.Genres) != 2 {
+		t.Fatalf("%+v %+v", c.Actors, c.Genres)
+	}
+	if len(c.Sample) != 2 || c.Sample[1].Image != "https://pics.test/abp00891jp-2.jpg" {
+		t.Fatalf("%+v", c.Sample)
+	}
+
+	grab.SetLanguage(LanguageEnglish)
+	if _, e := grab.Find("abp-891"); e == nil {
+		t.Fatal("dmm is japanese only")
+	}
+}
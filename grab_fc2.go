@@ -0,0 +1,192 @@
+package scrape
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// DefaultFc2MainPage ...
+const DefaultFc2MainPage = "https://adult.contents.fc2.com"
+const fc2Article = "/article/%s/"
+const fc2TimeFormat = "2006/01/02"
+
+var fc2NumberRegexp = regexp.MustCompile(`^\d{5,8}$`)
+var fc2DateRegexp = regexp.MustCompile(`\d{4}/\d{2}/\d{2}`)
+
+// ErrFc2Removed the article is deleted or not for sale anymore
+var ErrFc2Removed = fmt.Errorf("fc2 article removed: %w", ErrNotFound)
+
+// fc2Number return the article number of the ids like FC2-PPV-1234567,FC2PPV_1234567,FC2-1234567 or 1234567
+func fc2Number(id string) (string, bool) {
+	id = strings.TrimSpace(id)
+	if fc2NumberRegexp.MatchString(id) {
+		return id, true
+	}
+	match := idFC2Regexp.FindStringSubmatch(id)
+	if match == nil {
+		return "", false
+	}
+	return match[1], true
+}
+
+type grabFc2 struct {
+	mainPage string
+	language GrabLanguage
+	sample   bool
+	exact    bool
+	details  []*Content
+	cache    *Cache
+	force    bool
+}
+
+// MainPage ...
+func (g *grabFc2) MainPage(url string) {
+	g.mainPage = url
+}
+
+// SetSample ...
+func (g *grabFc2) SetSample(b bool) {
+	g.sample = b
+}
+
+// SetExact ...
+func (g *grabFc2) SetExact(b bool) {
+	g.exact = b
+}
+
+// SetLanguage ...
+func (g *grabFc2) SetLanguage(language GrabLanguage) {
+	g.language = language
+}
+
+// SetForce ...
+func (g *grabFc2) SetForce(force bool) {
+	g.force = force
+}
+
+// Name ...
+func (g *grabFc2) Name() string {
+	return "fc2"
+}
+
+// HasNext ...
+func (g *grabFc2) HasNext() bool {
+	return false
+}
+
+// Next ...
+func (g *grabFc2) Next() (IGrab, error) {
+	return nil, errors.New("fc2 has no next page")
+}
+
+// Result ...
+func (g *grabFc2) Result() ([]Content, error) {
+	var cs []Content
+	for _, c := range g.details {
+		cs = append(cs, *c)
+	}
+	return cs, nil
+}
+
+func (g *grabFc2) clone() *grabFc2 {
+	clone := new(grabFc2)
+	*clone = *g
+	clone.details = nil
+	return clone
+}
+
+// Clone ...
+func (g *grabFc2) Clone() IGrab {
+	return g.clone()
+}
+
+// Find ...
+func (g *grabFc2) Find(name string) (IGrab, error) {
+	clone := g.clone()
+	number, b := fc2Number(name)
+	if !b {
+		return clone, fmt.Errorf("%s is not a fc2 id", name)
+	}
+	url := clone.mainPage + fmt.Sprintf(fc2Article, number)
+	document, e := clone.cache.Query(url, clone.force)
+	if e != nil {
+		return clone, e
+	}
+	title := strings.TrimSpace(document.Find("div.items_article_headerInfo h3").First().Text())
+	if title == "" || document.Find("div.items_notfound_header").Length() > 0 {
+		//removed articles are not cached,they may come back
+		clone.cache.Delete(url)
+		return clone, fmt.Errorf("%s: %w", name, ErrFc2Removed)
+	}
+	content := &Content{
+		From:          clone.Name(),
+		Language:      clone.language.String(),
+		ID:            "FC2-PPV-" + number,
+		Title:         title,
+		OriginalTitle: title,
+		Studio:        strings.TrimSpace(document.Find("div.items_article_headerInfo a[href*='/users/']").First().Text()),
+		Poster:        fc2Link(document.Find("div.items_article_MainitemThumb img").First().AttrOr("src", "")),
+		Runtime:       parseRuntime(document.Find("div.items_article_MainitemThumb .items_article_info").First().Text()),
+		Plot:          strings.TrimSpace(document.Find("section.items_article_Contents div.items_article_Contents").First().Text()),
+	}
+	content.Thumb = content.Poster
+	if content.Plot != "" {
+		content.PlotFrom = clone.Name()
+	}
+	if date := fc2DateRegexp.FindString(document.Find("div.items_article_Releasedate").Text()); date != "" {
+		content.ReleaseDate, e = time.Parse(fc2TimeFormat, date)
+		if e == nil {
+			content.Year = strconv.Itoa(content.ReleaseDate.Year())
+		}
+	}
+	document.Find("section.items_article_TagArea a.tag").Each(func(i int, selection *goquery.Selection) {
+		link, _ := browseLink(clone.mainPage, selection.AttrOr("href", ""))
+		content.Genres = append(content.Genres, &Genre{
+			Content: strings.TrimSpace(selection.Text()),
+			URL:     link,
+		})
+	})
+	if clone.sample {
+		document.Find("ul.items_article_SampleImagesArea li a").Each(func(i int, selection *goquery.Selection) {
+			image := fc2Link(selection.AttrOr("href", ""))
+			content.Sample = append(content.Sample, &Sample{
+				Index: i,
+				Image: image,
+				Thumb: fc2Link(selection.Find("img").AttrOr("src", image)),
+			})
+		})
+	}
+	clone.details = append(clone.details, content)
+	return clone, nil
+}
+
+// fc2Link the images of fc2 are protocol relative
+func fc2Link(link string) string {
+	if strings.HasPrefix(link, "//") {
+		return "https:" + link
+	}
+	return link
+}
+
+// GrabFc2Options ...
+type GrabFc2Options func(fc2 *grabFc2)
+
+// NewGrabFc2 ...
+func NewGrabFc2(ops ...GrabFc2Options) IGrab {
+	grab := &grabFc2{
+		mainPage: DefaultFc2MainPage,
+		language: LanguageJapanese,
+		exact:    true,
+		cache:    NewCache(),
+	}
+	for _, op := range ops {
+		op(grab)
+	}
+	return grab
+}
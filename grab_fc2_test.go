@@ -0,0 +1,73 @@
+package scrape
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const testFc2Article = `<html><body>
+<div class="items_article_headerInfo"><h3> The Title </h3><ul><li><a href="https://adult.contents.fc2.com/users/seller/">Seller</a></li></ul></div>
+<div class="items_article_MainitemThumb"><span><img src="//storage.test/cover.jpg"><p class="items_article_info">01:02:40</p></span></div>
+<div class="items_article_Releasedate"><p>販売日 : 2021/01/02</p></div>
+<section class="items_article_TagArea"><a class="tag tagTag" href="/search/?tag=1">Tag</a></section>
+<ul class="items_article_SampleImagesArea"><li><a href="//storage.test/1.jpg"><img src="//storage.test/1s.jpg"></a></li></ul>
+</body></html>`
+
+const testFc2Removed = `<html><body><div class="items_notfound_header">not found</div></body></html>`
+
+// TestFc2Number ...
+func TestFc2Number(t *testing.T) {
+	for id, number := range map[string]string{
+		"FC2-PPV-1234567": "1234567",
+		"fc2ppv_1234567":  "1234567",
+		"FC2-1234567":     "1234567",
+		"1234567":         "1234567",
+		"ABP-891":         "",
+	} {
+		if v, _ := fc2Number(id); v != number {
+			t.Errorf("id %s: got %s want %s", id, v, number)
+		}
+	}
+}
+
+// TestGrabFc2 ...
+func TestGrabFc2(t *testing.T) {
+	testCache(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case fmt.Sprintf(fc2Article, "1234567"):
+			fmt.Fprint(w, testFc2Article)
+		default:
+			fmt.Fprint(w, testFc2Removed)
+		}
+	}))
+	defer server.Close()
+
+	grab := NewGrabFc2()
+	grab.MainPage(server.URL)
+	grab.SetSample(true)
+	grab.SetForce(true)
+	find, e := grab.Find("fc2ppv-1234567")
+	if e != nil {
+		t.Fatal(e)
+	}
+	contents, _ := find.Result()
+	if len(contents) != 1 {
+		t.Fatal(contents)
+	}
+	c := contents[0]
+	if c.ID != "FC2-PPV-1234567" || c.Title != "The Title" || c.Studio != "Seller" || c.Runtime != 63 || c.Year != "2021" {
+		t.Fatalf("%+v", c)
+	}
+	if c.Poster != "https://storage.test/cover.jpg" || len(c.Genres) != 1 || len(c.Sample) != 1 || c.Sample[0].Thumb != "https://storage.test/1s.jpg" {
+		t.Fatalf("%+v", c)
+	}
+
+	_, e = grab.Find("FC2-PPV-7654321")
+	if !errors.Is(e, ErrFc2Removed) || !errors.Is(e, ErrNotFound) {
+		t.Fatal(e)
+	}
+}
@@ -19,8 +19,13 @@ const javbusENURL = "/en/"
 const javbusKOURL = "/ko/"
 const javbusUncensored = "uncensored/search/%s"
 const javbusCensored = "search/%s"
+const javbusSearchStar = "searchstar/%s"
+const javbusGenre = "genre"
+const javbusStudio = "studio"
+const javbusSeries = "series"
 
 var grabJavbusLanguageList = []string{
+	LanguageChineseSimple:      javbusCNURL,
 	LanguageChineseTraditional: javbusCNURL,
 	LanguageEnglish:            javbusENURL,
 	LanguageJapanese:           javbusJAURL,
@@ -35,6 +40,7 @@ type grabJavbus struct {
 	exact      bool
 	finder     string
 	language   GrabLanguage
+	languages  []GrabLanguage
 	details    []*javbusSearchDetail
 	cache      *Cache
 	force      bool
@@ -96,7 +102,7 @@ func (g *grabJavbus) Result() (c []Content, e error) {
 			Uncensored:    detail.uncensored,
 			ID:            strings.ToUpper(detail.id),
 			Title:         detail.title,
-			OriginalTitle: "",
+			OriginalTitle: detail.originalTitle,
 			Year:          strconv.Itoa(detail.date.Year()),
 			Poster:        detail.bigImage,
 			Thumb:         detail.thumbImage,
@@ -105,10 +111,12 @@ func (g *grabJavbus) Result() (c []Content, e error) {
 			MovieSet:      detail.series,
 			Director:      detail.director,
 			Publisher:     detail.label,
+			Runtime:       parseRuntime(detail.length),
 			Plot:          "",
 			Genres:        detail.genre,
 			Actors:        detail.idols,
 			Sample:        detail.sample,
+			Localized:     detail.localized,
 		})
 	}
 	return
@@ -120,6 +128,11 @@ func (g *grabJavbus) clone() *grabJavbus {
 	return clone
 }
 
+// Clone ...
+func (g *grabJavbus) Clone() IGrab {
+	return g.clone()
+}
+
 func (g *grabJavbus) find(url string) (IGrab, error) {
 	clone := g.clone()
 	results, e := javbusSearchResultAnalyze(clone, url, g.force)
@@ -142,6 +155,10 @@ func (g *grabJavbus) find(url string) (IGrab, error) {
 		detail.uncensored = r.Uncensored
 		detail.thumbImage = r.PhotoFrame
 		detail.title = r.Title
+		if clone.language == LanguageJapanese {
+			detail.originalTitle = r.Title
+		}
+		javbusLocalizedAnalyze(clone, detail, g.force)
 		clone.details = append(clone.details, detail)
 		if debug {
 			log.Infow("find|detail", "id", detail.id, "detail", detail)
@@ -157,6 +174,9 @@ func (g *grabJavbus) find(url string) (IGrab, error) {
 // Find ...
 func (g *grabJavbus) Find(name string) (IGrab, error) {
 	g.finder = name
+	if !javbusLanguageSupported(g.language) {
+		return g, fmt.Errorf("javbus %s: %w", g.language, ErrLanguageNotSupported)
+	}
 	url := g.mainPage + grabJavbusLanguageList[g.language]
 	g.uncensored = false
 	grab, e := g.find(fmt.Sprintf(url+javbusCensored, name))
@@ -167,6 +187,113 @@ func (g *grabJavbus) Find(name string) (IGrab, error) {
 	return grab, nil
 }
 
+// ListByActor ...
+func (g *grabJavbus) ListByActor(actor string) ([]string, error) {
+	link, b := browseLink(g.mainPage, actor)
+	if !b {
+		url := g.mainPage + grabJavbusLanguageList[g.language] + fmt.Sprintf(javbusSearchStar, actor)
+		document, e := g.cache.Query(url, g.force)
+		if e != nil {
+			return nil, e
+		}
+		link, _ = browseLink(g.mainPage, document.Find("#waterfall > div > a.avatar-box").First().AttrOr("href", ""))
+		if link == "" {
+			return nil, fmt.Errorf("javbus actor %s not found", actor)
+		}
+	}
+	return g.list(link)
+}
+
+// ListByStudio ...
+func (g *grabJavbus) ListByStudio(studio string) ([]string, error) {
+	return g.listBy(studio, javbusStudio)
+}
+
+// ListBySeries ...
+func (g *grabJavbus) ListBySeries(series string) ([]string, error) {
+	return g.listBy(series, javbusSeries)
+}
+
+// listBy javbus has no search for studios and series,
+// the name is searched as a movie and the link with the name is taken from the detail pages of the result
+func (g *grabJavbus) listBy(value, path string) ([]string, error) {
+	link, b := browseLink(g.mainPage, value)
+	if !b {
+		clone := g.clone()
+		url := g.mainPage + grabJavbusLanguageList[g.language] + fmt.Sprintf(javbusCensored, value)
+		results, e := javbusSearchResultAnalyze(clone, url, g.force)
+		if e != nil {
+			return nil, e
+		}
+		for _, r := range results {
+			document, e := clone.cache.Query(r.DetailLink, g.force)
+			if e != nil {
+				log.Warnw("list", "link", r.DetailLink, "error", e)
+				continue
+			}
+			document.Find(fmt.Sprintf("div.info a[href*='/%s/']", path)).EachWithBreak(func(i int, selection *goquery.Selection) bool {
+				if strings.TrimSpace(selection.Text()) == value {
+					link, _ = browseLink(g.mainPage, selection.AttrOr("href", ""))
+					return false
+				}
+				return true
+			})
+			if link != "" {
+				break
+			}
+		}
+		if link == "" {
+			return nil, fmt.Errorf("javbus %s %s not found", path, value)
+		}
+	}
+	return g.list(link)
+}
+
+// ListByGenre ...
+func (g *grabJavbus) ListByGenre(genre string) ([]string, error) {
+	link, b := browseLink(g.mainPage, genre)
+	if !b {
+		document, e := g.cache.Query(g.mainPage+grabJavbusLanguageList[g.language]+javbusGenre, g.force)
+		if e != nil {
+			return nil, e
+		}
+		document.Find("div.genre-box > a").EachWithBreak(func(i int, selection *goquery.Selection) bool {
+			if strings.TrimSpace(selection.Text()) == genre {
+				link, _ = browseLink(g.mainPage, selection.AttrOr("href", ""))
+				return false
+			}
+			return true
+		})
+		if link == "" {
+			return nil, fmt.Errorf("javbus genre %s not found", genre)
+		}
+	}
+	return g.list(link)
+}
+
+// list collect the ids of every page start from url
+func (g *grabJavbus) list(url string) ([]string, error) {
+	var ids idSet
+	clone := g.clone()
+	for page := 1; url != ""; page++ {
+		results, e := javbusSearchResultAnalyze(clone, url, g.force)
+		if e != nil {
+			if page == 1 {
+				return nil, e
+			}
+			return ids.ids, &ListError{URL: url, Page: page, Err: e}
+		}
+		for _, r := range results {
+			ids.add(r.ID)
+		}
+		if debug {
+			log.Infow("list", "url", url, "page", page, "size", len(ids.ids))
+		}
+		url = clone.next
+	}
+	return ids.ids, nil
+}
+
 type javbusSearchResult struct {
 	Uncensored  bool
 	DetailLink  string
@@ -215,21 +342,23 @@ func javbusSearchResultAnalyze(grab *grabJavbus, url string, force bool) ([]*jav
 }
 
 type javbusSearchDetail struct {
-	language   string
-	title      string
-	thumbImage string
-	bigImage   string
-	id         string
-	date       time.Time
-	length     string
-	director   string
-	studio     string
-	label      string
-	series     string
-	genre      []*Genre
-	idols      []*Star
-	sample     []*Sample
-	uncensored bool
+	language      string
+	title         string
+	originalTitle string
+	localized     map[string]*Localized
+	thumbImage    string
+	bigImage      string
+	id            string
+	date          time.Time
+	length        string
+	director      string
+	studio        string
+	label         string
+	series        string
+	genre         []*Genre
+	idols         []*Star
+	sample        []*Sample
+	uncensored    bool
 }
 
 // AnalyzeLanguageFunc ...
@@ -283,6 +412,41 @@ var analyzeLanguageList = map[GrabLanguage][]string{
 		"類別",
 		"演員",
 	},
+	LanguageChineseSimple: {
+		"识别码",
+		"发行日期",
+		"长度",
+		"导演",
+		"制作商",
+		"发行商",
+		"系列",
+		"类别",
+		"演员",
+	},
+	LanguageKorea: {
+		"품번",
+		"발매일",
+		"재생 시간",
+		"감독",
+		"메이커",
+		"레이블",
+		"시리즈",
+		"장르",
+		"출연",
+	},
+}
+
+// javbus serves the chinese pages in traditional chinese only, so simple chinese also checks the traditional labels
+var analyzeLanguageFallback = map[GrabLanguage]GrabLanguage{
+	LanguageChineseSimple: LanguageChineseTraditional,
+}
+
+func javbusLanguageSupported(language GrabLanguage) bool {
+	if language < 0 || int(language) >= len(grabJavbusLanguageList) || grabJavbusLanguageList[language] == "" {
+		return false
+	}
+	_, b := analyzeLanguageList[language]
+	return b
 }
 
 func getAnalyzeLanguageFunc(language GrabLanguage, selection *goquery.Selection) AnalyzeLanguageFunc {
@@ -291,16 +455,22 @@ func getAnalyzeLanguageFunc(language GrabLanguage, selection *goquery.Selection)
 	if text == "" {
 		return javbusSearchDetailAnalyzeDummy
 	}
-	for idx, list := range analyzeLanguageList[language] {
-		ret := strings.Index(text, list)
-		if debug {
-			log.Infow("LanguageFunc", "ret", ret, "text", text, "list", list)
+	for {
+		for idx, list := range analyzeLanguageList[language] {
+			ret := strings.Index(text, list)
+			if debug {
+				log.Infow("LanguageFunc", "ret", ret, "text", text, "list", list)
+			}
+			if ret != -1 {
+				return analyzeLangFuncList[idx]
+			}
 		}
-		if ret != -1 {
-			return analyzeLangFuncList[idx]
+		fallback, b := analyzeLanguageFallback[language]
+		if !b {
+			return javbusSearchDetailAnalyzeDummy
 		}
+		language = fallback
 	}
-	return javbusSearchDetailAnalyzeDummy
 }
 func javbusSearchDetailAnalyzeDummy(selection *goquery.Selection, detail *javbusSearchDetail) (e error) {
 	text := goquery.NewDocumentFromNode(selection.Contents().Nodes[0]).Text()
@@ -472,19 +642,7 @@ func javbusSearchDetailAnalyze(grab *grabJavbus, result *javbusSearchResult, for
 	//detail.title, exists = document.Find("body > div.container > div.row.movie > div > a > img").Attr("title")
 	//log.With("bigTitle", detail.title).Info(exists)
 
-	document.Find("body > div.container > div.row.movie > div.col-md-3.info > p").Each(func(i int, selection *goquery.Selection) {
-		if debug {
-			html, e := selection.Html()
-			log.Infow("AnalyzeLanguageFunc", "language", grab.language, "source", html, "error", e)
-			selection.Contents().Each(func(i int, selection *goquery.Selection) {
-				log.Infow("AnalyzeLanguageFunc|Contents", "content", selection.Text())
-			})
-		}
-		err := getAnalyzeLanguageFunc(grab.language, selection)(selection, detail)
-		if err != nil {
-			log.Error(err)
-		}
-	})
+	javbusSearchDetailAnalyzeInfo(document, grab.language, detail)
 
 	if grab.sample {
 		document.Find("#sample-waterfall > a.sample-box").Each(func(i int, selection *goquery.Selection) {
@@ -506,6 +664,63 @@ func javbusSearchDetailAnalyze(grab *grabJavbus, result *javbusSearchResult, for
 	return detail, nil
 }
 
+func javbusSearchDetailAnalyzeInfo(document *goquery.Document, language GrabLanguage, detail *javbusSearchDetail) {
+	document.Find("body > div.container > div.row.movie > div.col-md-3.info > p").Each(func(i int, selection *goquery.Selection) {
+		if debug {
+			html, e := selection.Html()
+			log.Infow("AnalyzeLanguageFunc", "language", language, "source", html, "error", e)
+			selection.Contents().Each(func(i int, selection *goquery.Selection) {
+				log.Infow("AnalyzeLanguageFunc|Contents", "content", selection.Text())
+			})
+		}
+		err := getAnalyzeLanguageFunc(language, selection)(selection, detail)
+		if err != nil {
+			log.Error(err)
+		}
+	})
+}
+
+// javbusLocalizedAnalyze fetch the detail page again in every extra language,
+// the language of the grab is added from the detail itself
+func javbusLocalizedAnalyze(grab *grabJavbus, detail *javbusSearchDetail, force bool) {
+	detail.localized = map[string]*Localized{
+		grab.language.String(): {
+			Language: grab.language.String(),
+			Title:    detail.title,
+			Genres:   detail.genre,
+		},
+	}
+	for _, language := range grab.languages {
+		if language == grab.language {
+			continue
+		}
+		if !javbusLanguageSupported(language) {
+			log.Warnw("localized", "language", language, "error", ErrLanguageNotSupported)
+			continue
+		}
+		document, e := grab.cache.Query(grab.mainPage+grabJavbusLanguageList[language]+detail.id, force)
+		if e != nil {
+			log.Errorw("localized", "language", language, "id", detail.id, "error", e)
+			continue
+		}
+		tmp := &javbusSearchDetail{}
+		javbusSearchDetailAnalyzeInfo(document, language, tmp)
+		title := strings.TrimSpace(document.Find("body > div.container > h3").Text())
+		title = strings.TrimSpace(strings.TrimPrefix(title, detail.id))
+		if debug {
+			log.Infow("localized", "language", language, "title", title, "genre", tmp.genre)
+		}
+		if language == LanguageJapanese {
+			detail.originalTitle = title
+		}
+		detail.localized[language.String()] = &Localized{
+			Language: language.String(),
+			Title:    title,
+			Genres:   tmp.genre,
+		}
+	}
+}
+
 // GrabJavbusOptions ...
 type GrabJavbusOptions func(javbus *grabJavbus)
 
@@ -516,6 +731,13 @@ func JavbusLang(language GrabLanguage) GrabJavbusOptions {
 	}
 }
 
+// JavbusLanguages fetch the detail page in the other languages too
+func JavbusLanguages(languages ...GrabLanguage) GrabJavbusOptions {
+	return func(javbus *grabJavbus) {
+		javbus.languages = languages
+	}
+}
+
 // JavbusExact ...
 func JavbusExact(b bool) GrabJavbusOptions {
 	return func(javbus *grabJavbus) {
@@ -1,6 +1,26 @@
 package scrape
 
-import "testing"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const testJavbusLocalized = `<html><body><div class="container"><h3>ABC-001 %s</h3>
+<div class="row movie"><div class="col-md-3 info">
+<p class="header">%s:</p><p><span class="genre"><label><a href="/genre/1">%s</a></label></span></p>
+</div></div></div></body></html>`
+
+const testJavbusList = `<html><body><div id="waterfall">
+<div class="item"><a class="movie-box" href="%s/ABC-00%d"><div class="photo-frame"><img src="/a.jpg" title="title"></div>
+<div class="photo-info"><span><date>ABC-00%d</date><date>2021-01-02</date></span></div></a></div>
+</div><div class="text-center hidden-xs"><ul><li><a id="next" href="%s">next</a></li></ul></div></body></html>`
+
+const testJavbusStudioDetail = `<html><body><div class="container"><div class="row movie"><div class="col-md-3 info">
+<p><span class="header">製作商:</span> <a href="/studio/xx">Studio</a></p>
+</div></div></div></body></html>`
 
 // TestNewGrabJAVBUS ...
 func TestNewGrabJAVBUS(t *testing.T) {
@@ -21,3 +41,116 @@ func TestNewGrabJAVBUS(t *testing.T) {
 		count++
 	}
 }
+
+// TestJavbusLanguageSupported ...
+func TestJavbusLanguageSupported(t *testing.T) {
+	for lang := range languageGrabStringList {
+		if !javbusLanguageSupported(lang) {
+			t.Errorf("language %s is not supported", lang)
+		}
+	}
+	if javbusLanguageSupported(GrabLanguage(len(languageGrabStringList))) {
+		t.Error("unknown language is supported")
+	}
+}
+
+// TestJavbusLocalized ...
+func TestJavbusLocalized(t *testing.T) {
+	testCache(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case javbusJAURL + "ABC-001":
+			fmt.Fprintf(w, testJavbusLocalized, "日本語のタイトル", "ジャンル", "巨乳")
+		case javbusENURL + "ABC-001":
+			fmt.Fprintf(w, testJavbusLocalized, "English Title", "Genre", "Big Tits")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	grab := NewGrabJavbus(JavbusLanguages(LanguageChineseTraditional, LanguageJapanese, LanguageEnglish, LanguageKorea)).(*grabJavbus)
+	grab.MainPage(server.URL)
+	detail := &javbusSearchDetail{id: "ABC-001", title: "中文標題", genre: []*Genre{{Content: "巨乳"}}}
+	javbusLocalizedAnalyze(grab, detail, true)
+	if detail.originalTitle != "日本語のタイトル" {
+		t.Errorf("original title: %s", detail.originalTitle)
+	}
+	//the language of the grab is taken from the detail and the missing page is skipped
+	if len(detail.localized) != 3 {
+		t.Fatal(detail.localized)
+	}
+	for lang, want := range map[GrabLanguage][2]string{
+		LanguageChineseTraditional: {"中文標題", "巨乳"},
+		LanguageJapanese:           {"日本語のタイトル", "巨乳"},
+		LanguageEnglish:            {"English Title", "Big Tits"},
+	} {
+		l, b := detail.localized[lang.String()]
+		if !b {
+			t.Fatalf("no %s", lang)
+		}
+		if l.Title != want[0] || len(l.Genres) != 1 || l.Genres[0].Content != want[1] {
+			t.Errorf("%s: %+v", lang, l)
+		}
+	}
+}
+
+// TestJavbusListByStudio ...
+func TestJavbusListByStudio(t *testing.T) {
+	testCache(t)
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/search/Studio":
+			fmt.Fprintf(w, testJavbusList, server.URL, 1, 1, "")
+		case "/ABC-001":
+			fmt.Fprint(w, testJavbusStudioDetail)
+		case "/studio/xx":
+			fmt.Fprintf(w, testJavbusList, server.URL, 1, 1, "/studio/xx/2")
+		case "/studio/xx/2":
+			fmt.Fprintf(w, testJavbusList, server.URL, 2, 2, "/studio/xx/3")
+		default:
+			http.Error(w, "error", http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	grab := NewGrabJavbus().(*grabJavbus)
+	grab.MainPage(server.URL)
+	ids, e := grab.ListByStudio("Studio")
+	var listError *ListError
+	if !errors.As(e, &listError) || listError.Page != 3 {
+		t.Fatal(e)
+	}
+	if len(ids) != 2 || ids[0] != "ABC-001" || ids[1] != "ABC-002" {
+		t.Fatal(ids)
+	}
+	if _, e := grab.ListBySeries("Studio"); e == nil {
+		t.Fatal("the series is not on the detail page")
+	}
+}
+
+// TestJavbusListByGenre ...
+func TestJavbusListByGenre(t *testing.T) {
+	testCache(t)
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/genre":
+			fmt.Fprint(w, `<html><body><div class="genre-box"><a href="/genre/1">巨乳</a></div></body></html>`)
+		case "/genre/1":
+			fmt.Fprintf(w, testJavbusList, server.URL, 1, 1, "")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	grab := NewGrabJavbus().(*grabJavbus)
+	grab.MainPage(server.URL)
+	grab.SetForce(true)
+	ids, e := grab.ListByGenre("巨乳")
+	if e != nil || len(ids) != 1 || ids[0] != "ABC-001" {
+		t.Fatal(ids, e)
+	}
+}
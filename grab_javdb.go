@@ -3,6 +3,7 @@ package scrape
 import (
 	"errors"
 	"fmt"
+	"net/http"
 	"strconv"
 	"strings"
 	"time"
@@ -14,6 +15,10 @@ import (
 // DefaultJavdbMainPage ...
 const DefaultJavdbMainPage = "https://javdb7.com"
 const javdbSearch = "/search?q=%s&f=all"
+const javdbSearchActor = "/search?q=%s&f=actor"
+const javdbSearchStudio = "/search?q=%s&f=maker"
+const javdbSearchSeries = "/search?q=%s&f=series"
+const javdbGenre = "/tags"
 
 const javdbENRUL = "locale=en"
 const javdbZHRUL = "locale=zh"
@@ -30,6 +35,17 @@ const javdbZHGenre = "類別"
 const javdbZHSeries = "系列"
 const javdbZHTimeFormat = "2006-01-02"
 const javdbZHRating = "評分"
+const javdbZHHD = "高清"
+const javdbZHSubtitle = "字幕"
+
+func init() {
+	//javdb shows the over 18 modal on every page until it is confirmed
+	for _, host := range []string{"javdb.com", "javdb7.com"} {
+		siteCookies[host] = []*http.Cookie{
+			{Name: "over18", Value: "1"},
+		}
+	}
+}
 
 type grabJavdb struct {
 	mainPage string
@@ -41,6 +57,7 @@ type grabJavdb struct {
 	details  []*javdbSearchDetail
 	cache    *Cache
 	force    bool
+	account  *javdbAccount
 }
 
 func (g *grabJavdb) SetForce(force bool) {
@@ -48,7 +65,7 @@ func (g *grabJavdb) SetForce(force bool) {
 }
 
 func (g *grabJavdb) SetLanguage(language GrabLanguage) {
-	//do nothing
+	g.language = language
 }
 
 // SetExact ...
@@ -70,6 +87,11 @@ func (g *grabJavdb) clone() *grabJavdb {
 	return clone
 }
 
+// Clone ...
+func (g *grabJavdb) Clone() IGrab {
+	return g.clone()
+}
+
 // HasNext ...
 func (g *grabJavdb) HasNext() bool {
 	return g.next != ""
@@ -131,10 +153,91 @@ func (g *grabJavdb) find(url string) (IGrab, error) {
 // Find ...
 func (g *grabJavdb) Find(name string) (IGrab, error) {
 	g.finder = name
+	if g.language != LanguageChineseTraditional {
+		//detail labels are only parsed in traditional chinese
+		return g, fmt.Errorf("javdb %s: %w", g.language, ErrLanguageNotSupported)
+	}
 	url := fmt.Sprintf(g.mainPage+javdbSearch, name)
 	return g.find(url)
 }
 
+// ListByActor ...
+func (g *grabJavdb) ListByActor(actor string) ([]string, error) {
+	return g.listBy(actor, javdbSearchActor, "#actors > div.box.actor-box > a")
+}
+
+// ListByStudio ...
+func (g *grabJavdb) ListByStudio(studio string) ([]string, error) {
+	return g.listBy(studio, javdbSearchStudio, "#makers > div.box > a")
+}
+
+// ListBySeries ...
+func (g *grabJavdb) ListBySeries(series string) ([]string, error) {
+	return g.listBy(series, javdbSearchSeries, "#series > div.box > a")
+}
+
+// ListByGenre ...
+func (g *grabJavdb) ListByGenre(genre string) ([]string, error) {
+	link, b := browseLink(g.mainPage, genre)
+	if !b {
+		document, e := g.query(g.mainPage+javdbGenre, g.force)
+		if e != nil {
+			return nil, e
+		}
+		document.Find("#tags > dl > dd > a").EachWithBreak(func(i int, selection *goquery.Selection) bool {
+			if strings.TrimSpace(selection.Text()) == genre {
+				link = g.mainPage + selection.AttrOr("href", "")
+				return false
+			}
+			return true
+		})
+		if link == "" {
+			return nil, fmt.Errorf("javdb genre %s not found", genre)
+		}
+	}
+	return g.list(link)
+}
+
+// listBy search the name with the search url and list the first matched link
+func (g *grabJavdb) listBy(value, search, selector string) ([]string, error) {
+	link, b := browseLink(g.mainPage, value)
+	if !b {
+		document, e := g.query(fmt.Sprintf(g.mainPage+search, value), g.force)
+		if e != nil {
+			return nil, e
+		}
+		href := document.Find(selector).First().AttrOr("href", "")
+		if href == "" {
+			return nil, fmt.Errorf("javdb %s not found", value)
+		}
+		link, _ = browseLink(g.mainPage, href)
+	}
+	return g.list(link)
+}
+
+// list collect the ids of every page start from url
+func (g *grabJavdb) list(url string) ([]string, error) {
+	var ids idSet
+	clone := g.clone()
+	for page := 1; url != ""; page++ {
+		results, e := javdbSearchResultAnalyze(clone, url, g.force)
+		if e != nil {
+			if page == 1 {
+				return nil, e
+			}
+			return ids.ids, &ListError{URL: url, Page: page, Err: e}
+		}
+		for _, r := range results {
+			ids.add(r.ID)
+		}
+		if debug {
+			log.Infow("list", "url", url, "page", page, "size", len(ids.ids))
+		}
+		url = clone.next
+	}
+	return ids.ids, nil
+}
+
 type javdbSearchDetail struct {
 	title      string
 	thumbImage string
@@ -151,13 +254,17 @@ type javdbSearchDetail struct {
 	uncensored bool
 	rating     string
 	publisher  string
+	reviews    []*Review
+	userRating float64
+	magnets    []*Magnet
+	preview    string
 }
 
 func javdbSearchDetailAnalyze(grab *grabJavdb, result *javdbSearchResult, force bool) (detail *javdbSearchDetail, e error) {
 	if result == nil || result.DetailLink == "" {
 		return nil, errors.New("javdb search result is null")
 	}
-	document, e := grab.cache.Query(grab.mainPage+result.DetailLink, force)
+	document, e := grab.query(grab.mainPage+result.DetailLink, force)
 	if e != nil {
 		return nil, e
 	}
@@ -216,13 +323,55 @@ func javdbSearchDetailAnalyze(grab *grabJavdb, result *javdbSearchResult, force
 		}
 	})
 
+	//the reviews are only shown to members
+	document.Find("div.review-items div.review-item").Each(func(i int, selection *goquery.Selection) {
+		detail.reviews = append(detail.reviews, &Review{
+			User:  strings.TrimSpace(selection.Find(".review-title").Contents().First().Text()),
+			Score: float64(selection.Find(".score-stars i.icon-star").Not(".gray").Length()),
+			Date:  strings.TrimSpace(selection.Find(".time").Text()),
+			Text:  strings.TrimSpace(selection.Find(".content").Text()),
+		})
+	})
+
+	//the magnets,the preview video and the rating of the account are only shown to members
+	document.Find("#magnets-content .item").Each(func(i int, selection *goquery.Selection) {
+		link := selection.Find("a[href^='magnet:']").First()
+		if link.Length() == 0 {
+			return
+		}
+		magnet := &Magnet{
+			Name: strings.TrimSpace(link.Find(".name").Text()),
+			Link: link.AttrOr("href", ""),
+			Size: strings.TrimSpace(strings.Split(link.Find(".meta").Text(), ",")[0]),
+			Date: strings.TrimSpace(selection.Find(".time").Text()),
+		}
+		selection.Find(".tags .tag").Each(func(i int, selection *goquery.Selection) {
+			switch strings.TrimSpace(selection.Text()) {
+			case javdbZHHD:
+				magnet.HD = true
+			case javdbZHSubtitle:
+				magnet.Subtitle = true
+			}
+		})
+		detail.magnets = append(detail.magnets, magnet)
+	})
+	detail.preview = document.Find("#preview-video source").AttrOr("src", "")
+	if strings.Index(detail.preview, "//") == 0 {
+		detail.preview = "https:" + detail.preview
+	}
+	if score := document.Find("#video-review-form input[name='video_review[score]'][checked]").AttrOr("value", ""); score != "" {
+		detail.userRating, _ = strconv.ParseFloat(score, 64)
+	}
+
 	if grab.sample {
+		//members get every sample and the thumbs
 		document.Find("div.message-body > div.tile-images.preview-images > a.tile-item").Each(func(i int, selection *goquery.Selection) {
 			image := selection.AttrOr("href", "")
 			title := selection.AttrOr("data-caption", "")
 			detail.sample = append(detail.sample, &Sample{
 				Index: i,
 				Image: image,
+				Thumb: selection.Find("img").AttrOr("src", ""),
 				Title: title,
 			})
 			if debug {
@@ -245,7 +394,7 @@ type javdbSearchResult struct {
 
 //tag:SearchResultAnalyze
 func javdbSearchResultAnalyze(grab *grabJavdb, url string, force bool) (result []*javdbSearchResult, e error) {
-	document, e := grab.cache.Query(url, force)
+	document, e := grab.query(url, force)
 	if e != nil {
 		return nil, e
 	}
@@ -308,6 +457,12 @@ func (g *grabJavdb) Result() (c []Content, e error) {
 			MovieSet:      detail.series,
 			Director:      detail.director,
 			Publisher:     detail.publisher,
+			Runtime:       parseRuntime(detail.length),
+			Rating:        parseScore(detail.rating),
+			Reviews:       detail.reviews,
+			UserRating:    detail.userRating,
+			Magnets:       detail.magnets,
+			Preview:       detail.preview,
 			Plot:          "",
 			Genres:        detail.genre,
 			Actors:        detail.idols,
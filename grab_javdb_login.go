@@ -0,0 +1,224 @@
+package scrape
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/goextension/log"
+)
+
+const javdbLogin = "/login"
+const javdbUserSessions = "/user_sessions"
+
+// the session cookies of javdb,remember_me_token keeps the login after _jdb_session expires
+var javdbSessionCookies = []string{"remember_me_token", "_jdb_session"}
+
+// ErrJavdbLogin ...
+var ErrJavdbLogin = errors.New("javdb login failed")
+
+// logins of the grabs sharing a cache are serialized
+var javdbLoginLock sync.Mutex
+
+type javdbAccount struct {
+	email    string
+	password string
+}
+
+// JavdbLogin login with the account,the session is persisted with the cookies of the cache
+// and the grab logs in again when the session is expired
+func JavdbLogin(email, password string) GrabJavdbOptions {
+	return func(javdb *grabJavdb) {
+		if email == "" || password == "" {
+			javdb.account = nil
+			return
+		}
+		javdb.account = &javdbAccount{
+			email:    email,
+			password: password,
+		}
+	}
+}
+
+func (g *grabJavdb) host() string {
+	u, e := url.Parse(g.mainPage)
+	if e != nil {
+		return ""
+	}
+	return u.Hostname()
+}
+
+// hasSession check the cache has the session cookies of javdb
+func (g *grabJavdb) hasSession() bool {
+	return g.session() != ""
+}
+
+// session the values of the session cookies in the cache,a new login changes it
+func (g *grabJavdb) session() string {
+	var values []string
+	for _, cookie := range g.cache.Cookies(g.host()) {
+		if javdbSessionCookie(cookie.Name) && cookie.Value != "" {
+			values = append(values, cookie.Name+"="+cookie.Value)
+		}
+	}
+	return strings.Join(values, ";")
+}
+
+// javdbLoggedIn the logout link is only shown to members
+func javdbLoggedIn(document *goquery.Document) bool {
+	return document.Find("a[href$='/logout']").Length() > 0
+}
+
+// query get the page as a member when the grab has an account
+func (g *grabJavdb) query(url string, force bool) (*goquery.Document, error) {
+	if g.account == nil {
+		return g.cache.Query(url, force)
+	}
+	if !g.hasSession() {
+		if e := g.login(""); e != nil {
+			return nil, e
+		}
+	}
+	stale := g.session()
+	document, e := g.cache.Query(url, force)
+	if e != nil || javdbLoggedIn(document) {
+		return document, e
+	}
+	//the page may be cached before the login,get it again first
+	document, e = g.cache.ForceQuery(url)
+	if e != nil || javdbLoggedIn(document) {
+		return document, e
+	}
+	log.Infow("javdb", "session", "expired", "url", url)
+	g.cache.Delete(url)
+	if e := g.login(stale); e != nil {
+		return nil, e
+	}
+	document, e = g.cache.ForceQuery(url)
+	if e != nil {
+		return nil, e
+	}
+	if !javdbLoggedIn(document) {
+		g.cache.Delete(url)
+		return nil, fmt.Errorf("%w: not logged in after login", ErrJavdbLogin)
+	}
+	return document, nil
+}
+
+// login post the account and keep the session cookies in the cache,
+// the login is skipped when another grab has replaced the stale session while waiting
+func (g *grabJavdb) login(stale string) error {
+	javdbLoginLock.Lock()
+	defer javdbLoginLock.Unlock()
+	if session := g.session(); session != "" && session != stale {
+		return nil
+	}
+	jar, e := cookiejar.New(nil)
+	if e != nil {
+		return e
+	}
+	client := &http.Client{
+		Jar:     jar,
+		Timeout: 60 * time.Second,
+	}
+	if cli != nil {
+		client.Transport = cli.Transport
+	}
+	u, e := url.Parse(g.mainPage)
+	if e != nil {
+		return e
+	}
+	//the challenge cookies are still needed to reach the login page
+	var cookies []*http.Cookie
+	for _, cookie := range g.cache.Cookies(u.Host) {
+		if !javdbSessionCookie(cookie.Name) {
+			cookies = append(cookies, cookie)
+		}
+	}
+	jar.SetCookies(u, cookies)
+
+	body, e := javdbLoginRequest(client, http.MethodGet, g.mainPage+javdbLogin, nil)
+	if e != nil {
+		return e
+	}
+	document, e := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if e != nil {
+		return e
+	}
+	token := document.Find("input[name='authenticity_token']").AttrOr("value", "")
+	if token == "" {
+		return fmt.Errorf("%w: no authenticity token", ErrJavdbLogin)
+	}
+	form := url.Values{
+		"authenticity_token": {token},
+		"email":              {g.account.email},
+		"password":           {g.account.password},
+		"remember":           {"1"},
+	}
+	body, e = javdbLoginRequest(client, http.MethodPost, g.mainPage+javdbUserSessions, form)
+	if e != nil {
+		return e
+	}
+	document, e = goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if e != nil {
+		return e
+	}
+	if !javdbLoggedIn(document) {
+		return fmt.Errorf("%w: %s", ErrJavdbLogin, strings.TrimSpace(document.Find("div.message-body, .notification").First().Text()))
+	}
+	var session []*http.Cookie
+	for _, cookie := range jar.Cookies(u) {
+		if javdbSessionCookie(cookie.Name) {
+			session = append(session, cookie)
+		}
+	}
+	if len(session) == 0 {
+		return fmt.Errorf("%w: no session cookie", ErrJavdbLogin)
+	}
+	log.Infow("javdb", "login", g.account.email)
+	return g.cache.SetCookies(u.Hostname(), session...)
+}
+
+func javdbSessionCookie(name string) bool {
+	for _, v := range javdbSessionCookies {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
+
+func javdbLoginRequest(client *http.Client, method, link string, form url.Values) ([]byte, error) {
+	req, e := http.NewRequest(method, link, strings.NewReader(form.Encode()))
+	if e != nil {
+		return nil, e
+	}
+	req.Header.Set("user-agent", UserAgent)
+	if form != nil {
+		req.Header.Set("content-type", "application/x-www-form-urlencoded")
+	}
+	res, e := client.Do(req)
+	if e != nil {
+		return nil, e
+	}
+	defer res.Body.Close()
+	bys, e := ioutil.ReadAll(res.Body)
+	if e != nil {
+		return nil, e
+	}
+	if reason := challengeReason(bys, res.StatusCode, res.Request.URL.String()); reason != "" {
+		return nil, &BlockedError{Site: req.URL.Host, URL: link, Reason: reason}
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, &StatusError{URL: link, Code: res.StatusCode, Status: res.Status, Body: bys}
+	}
+	return bys, nil
+}
@@ -1,6 +1,14 @@
 package scrape
 
-import "testing"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+)
 
 // TestNewJavdb ...
 func TestNewJavdb(t *testing.T) {
@@ -21,3 +29,119 @@ func TestNewJavdb(t *testing.T) {
 	}
 
 }
+
+const testJavdbLogout = `<a class="navbar-item" href="/logout" data-method="delete">logout</a>`
+
+const testJavdbSearch = `<html><body>%s<section><div id="videos"><div><div class="grid-item column">
+<a class="box" href="/v/abc"><div><img data-src="//img.test/thumb.jpg"></div><div class="uid">ABP-891</div><div class="video-title">The Title</div></a>
+</div></div></div></section></body></html>`
+
+const testJavdbDetail = `<html><body>` + testJavdbLogout + `
+<div class="columns"><div><nav class="panel">
+<div class="panel-block"><strong>番號:</strong><span class="value">ABP-891</span></div>
+<div class="panel-block"><strong>評分:</strong><span class="value">4.47分, 由1014人評價</span></div>
+</nav></div></div>
+<div id="magnets-content"><div class="item columns"><div class="magnet-name column"><a href="magnet:?xt=urn:btih:abc"><span class="name">ABP-891-C</span><br><span class="meta">5.71GB, 1個文件</span><div class="tags"><span class="tag">高清</span><span class="tag">字幕</span></div></a></div><div class="date column"><span class="time">2021-01-02</span></div></div></div>
+<video id="preview-video"><source src="//video.test/abp891.mp4" type="video/mp4"></video>
+<form id="video-review-form"><input type="radio" name="video_review[score]" value="4"><input type="radio" name="video_review[score]" value="5" checked></form>
+<div class="message-body"><div class="tile-images preview-images"><a class="tile-item" href="https://img.test/1.jpg" data-caption="1"><img src="https://img.test/1s.jpg"></a><a class="tile-item" href="https://img.test/2.jpg" data-caption="2"><img src="https://img.test/2s.jpg"></a></div></div>
+<div class="review-items"><div class="review-item"><div class="review-title">user1 <span class="score-stars"><i class="icon-star"></i><i class="icon-star"></i><i class="icon-star gray"></i></span><span class="time">2021-01-01</span></div><div class="content"><p>good</p></div></div></div>
+</body></html>`
+
+// TestJavdbLogin ...
+func TestJavdbLogin(t *testing.T) {
+	logins := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		member := false
+		if cookie, e := r.Cookie("remember_me_token"); e == nil && cookie.Value == "valid" {
+			member = true
+		}
+		switch r.URL.Path {
+		case javdbLogin:
+			http.SetCookie(w, &http.Cookie{Name: "_jdb_session", Value: "anonymous", Path: "/"})
+			fmt.Fprint(w, `<form><input name="authenticity_token" value="token"></form>`)
+		case javdbUserSessions:
+			if r.FormValue("authenticity_token") != "token" || r.FormValue("email") != "user@test" || r.FormValue("password") != "password" {
+				fmt.Fprint(w, `<div class="message-body">wrong password</div>`)
+				return
+			}
+			logins++
+			http.SetCookie(w, &http.Cookie{Name: "remember_me_token", Value: "valid", Path: "/"})
+			http.Redirect(w, r, "/", http.StatusFound)
+		case "/":
+			if member {
+				fmt.Fprint(w, testJavdbLogout)
+			}
+		case "/search":
+			logout := ""
+			if member {
+				logout = testJavdbLogout
+			}
+			fmt.Fprintf(w, testJavdbSearch, logout)
+		case "/v/abc":
+			fmt.Fprint(w, testJavdbDetail)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	c := testCache(t)
+	//an expired session from the last run
+	if e := c.SetCookies(u.Hostname(), &http.Cookie{Name: "remember_me_token", Value: "expired"}); e != nil {
+		t.Fatal(e)
+	}
+
+	grabs := make([]IGrab, 4)
+	for i := range grabs {
+		grabs[i] = NewGrabJavdb(JavdbLogin("user@test", "password"))
+		grabs[i].MainPage(server.URL)
+		grabs[i].SetSample(true)
+		grabs[i].SetForce(true)
+	}
+	//the grabs waiting for the login use the new session
+	wg := &sync.WaitGroup{}
+	results := make([][]Content, len(grabs))
+	for i, grab := range grabs {
+		wg.Add(1)
+		go func(i int, grab IGrab) {
+			defer wg.Done()
+			find, e := grab.Find("abp-891")
+			if e != nil {
+				t.Error(e)
+				return
+			}
+			results[i], _ = find.Result()
+		}(i, grab)
+	}
+	wg.Wait()
+	contents := results[0]
+	if len(contents) != 1 || logins != 1 {
+		t.Fatal(contents, logins)
+	}
+	content := contents[0]
+	if content.Rating != 4.47 || len(content.Reviews) != 1 || content.Reviews[0].Score != 2 || content.Reviews[0].User != "user1" {
+		t.Fatalf("%+v %+v", content, content.Reviews)
+	}
+	if content.UserRating != 5 || content.Preview != "https://video.test/abp891.mp4" || len(content.Sample) != 2 || content.Sample[1].Thumb != "https://img.test/2s.jpg" {
+		t.Fatalf("%+v %+v", content, content.Sample)
+	}
+	if len(content.Magnets) != 1 || content.Magnets[0].Name != "ABP-891-C" || content.Magnets[0].Size != "5.71GB" || !content.Magnets[0].HD || !content.Magnets[0].Subtitle || content.Magnets[0].Date != "2021-01-02" {
+		t.Fatalf("%+v", content.Magnets)
+	}
+
+	//the expired session is replaced by a new login
+	if e := c.SetCookies(u.Hostname(), &http.Cookie{Name: "remember_me_token", Value: "expired"}); e != nil {
+		t.Fatal(e)
+	}
+	if _, e := grabs[0].Find("abp-891"); e != nil || logins != 2 {
+		t.Fatal(e, logins)
+	}
+
+	wrong := NewGrabJavdb(JavdbLogin("user@test", "wrong"))
+	wrong.MainPage(server.URL)
+	_ = c.ClearCookies(u.Hostname())
+	if _, e := wrong.Find("abp-892"); !errors.Is(e, ErrJavdbLogin) {
+		t.Fatal(e)
+	}
+}
@@ -0,0 +1,278 @@
+package scrape
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/goextension/log"
+)
+
+// DefaultJavlibraryMainPage ...
+const DefaultJavlibraryMainPage = "https://www.javlibrary.com"
+const javlibrarySearch = "/vl_searchbyid.php?keyword=%s"
+const javlibraryReviews = "/videoreviews.php?v=%s&mode=2"
+const javlibraryTimeFormat = "2006-01-02"
+
+var grabJavlibraryLanguageList = map[GrabLanguage]string{
+	LanguageEnglish:            "/en",
+	LanguageJapanese:           "/ja",
+	LanguageChineseSimple:      "/cn",
+	LanguageChineseTraditional: "/tw",
+}
+
+type grabJavlibrary struct {
+	mainPage string
+	language GrabLanguage
+	sample   bool
+	exact    bool
+	reviews  int
+	finder   string
+	details  []*Content
+	cache    *Cache
+	force    bool
+}
+
+// MainPage ...
+func (g *grabJavlibrary) MainPage(url string) {
+	g.mainPage = url
+}
+
+// SetSample ...
+func (g *grabJavlibrary) SetSample(b bool) {
+	g.sample = b
+}
+
+// SetExact ...
+func (g *grabJavlibrary) SetExact(b bool) {
+	g.exact = b
+}
+
+// SetLanguage ...
+func (g *grabJavlibrary) SetLanguage(language GrabLanguage) {
+	g.language = language
+}
+
+// SetForce ...
+func (g *grabJavlibrary) SetForce(force bool) {
+	g.force = force
+}
+
+// Name ...
+func (g *grabJavlibrary) Name() string {
+	return "javlibrary"
+}
+
+// HasNext ...
+func (g *grabJavlibrary) HasNext() bool {
+	return false
+}
+
+// Next ...
+func (g *grabJavlibrary) Next() (IGrab, error) {
+	return nil, errors.New("javlibrary has no next page")
+}
+
+// Result ...
+func (g *grabJavlibrary) Result() ([]Content, error) {
+	var cs []Content
+	for _, c := range g.details {
+		cs = append(cs, *c)
+	}
+	return cs, nil
+}
+
+func (g *grabJavlibrary) clone() *grabJavlibrary {
+	clone := new(grabJavlibrary)
+	*clone = *g
+	clone.details = nil
+	return clone
+}
+
+// Clone ...
+func (g *grabJavlibrary) Clone() IGrab {
+	return g.clone()
+}
+
+func (g *grabJavlibrary) languagePage() string {
+	return g.mainPage + grabJavlibraryLanguageList[g.language]
+}
+
+// Find ...
+func (g *grabJavlibrary) Find(name string) (IGrab, error) {
+	if _, b := grabJavlibraryLanguageList[g.language]; !b {
+		return g, fmt.Errorf("javlibrary %s: %w", g.language, ErrLanguageNotSupported)
+	}
+	clone := g.clone()
+	clone.finder = strings.ToUpper(strings.TrimSpace(name))
+	document, e := clone.cache.Query(clone.languagePage()+fmt.Sprintf(javlibrarySearch, url.QueryEscape(clone.finder)), clone.force)
+	if e != nil {
+		return clone, e
+	}
+	//the search redirects to the detail page when only one movie matches
+	if document.Find("#video_id").Length() > 0 {
+		content, e := clone.detail(document)
+		if e != nil {
+			return clone, e
+		}
+		clone.details = append(clone.details, content)
+		return clone, nil
+	}
+	var err error
+	var links []string
+	document.Find("div.videos div.video a").Each(func(i int, selection *goquery.Selection) {
+		id := strings.TrimSpace(selection.Find("div.id").Text())
+		if clone.exact && !strings.EqualFold(id, clone.finder) {
+			return
+		}
+		href := strings.TrimPrefix(selection.AttrOr("href", ""), ".")
+		if strings.HasPrefix(href, "/?") {
+			links = append(links, clone.languagePage()+href)
+		}
+	})
+	if len(links) == 0 {
+		return clone, errors.New("no data found")
+	}
+	for _, link := range links {
+		var content *Content
+		detail, e := clone.cache.Query(link, clone.force)
+		if e == nil {
+			content, e = clone.detail(detail)
+		}
+		if e != nil {
+			log.Warnw("javlibrary", "link", link, "error", e)
+			err = e
+			continue
+		}
+		clone.details = append(clone.details, content)
+		//the same id has the blu-ray and dvd versions
+		if clone.exact {
+			break
+		}
+	}
+	if len(clone.details) == 0 {
+		return clone, err
+	}
+	return clone, nil
+}
+
+func (g *grabJavlibrary) detail(document *goquery.Document) (*Content, error) {
+	id := strings.ToUpper(strings.TrimSpace(document.Find("#video_id td.text").Text()))
+	if id == "" {
+		return nil, errors.New("no data found")
+	}
+	title := strings.TrimSpace(document.Find("#video_title h3 a").First().Text())
+	title = strings.TrimSpace(strings.TrimPrefix(title, id))
+	poster := document.Find("#video_jacket_img").AttrOr("src", "")
+	if strings.HasPrefix(poster, "//") {
+		poster = "https:" + poster
+	}
+	content := &Content{
+		From:      g.Name(),
+		Language:  g.language.String(),
+		ID:        id,
+		Title:     title,
+		Director:  strings.TrimSpace(document.Find("#video_director td.text").Text()),
+		Studio:    strings.TrimSpace(document.Find("#video_maker td.text").Text()),
+		Publisher: strings.TrimSpace(document.Find("#video_label td.text").Text()),
+		Runtime:   parseRuntime(document.Find("#video_length span.text").Text()),
+		Rating:    parseScore(document.Find("#video_review span.score").Text()),
+		Poster:    poster,
+		Thumb:     strings.Replace(poster, "pl.jpg", "ps.jpg", 1),
+	}
+	if g.language == LanguageJapanese {
+		content.OriginalTitle = title
+	}
+	date := strings.TrimSpace(document.Find("#video_date td.text").Text())
+	if date != "" {
+		parse, e := time.Parse(javlibraryTimeFormat, date)
+		if e != nil {
+			log.Warnw("javlibrary", "date", date, "error", e)
+		} else {
+			content.ReleaseDate = parse
+			content.Year = strconv.Itoa(parse.Year())
+		}
+	}
+	document.Find("#video_genres span.genre a").Each(func(i int, selection *goquery.Selection) {
+		content.Genres = append(content.Genres, &Genre{
+			Content: strings.TrimSpace(selection.Text()),
+			URL:     g.languagePage() + "/" + strings.TrimPrefix(selection.AttrOr("href", ""), "/"),
+		})
+	})
+	document.Find("#video_cast span.star a").Each(func(i int, selection *goquery.Selection) {
+		content.Actors = append(content.Actors, &Star{
+			Name: strings.TrimSpace(selection.Text()),
+			Link: g.languagePage() + "/" + strings.TrimPrefix(selection.AttrOr("href", ""), "/"),
+		})
+	})
+	if g.exact && g.finder != "" && content.ID != g.finder {
+		return nil, fmt.Errorf("javlibrary id %s is not %s", content.ID, g.finder)
+	}
+	if g.reviews > 0 {
+		content.Reviews = g.topReviews(document)
+	}
+	return content, nil
+}
+
+// topReviews the reviews sorted by the votes,a failure only loses the reviews
+func (g *grabJavlibrary) topReviews(document *goquery.Document) []*Review {
+	link, b := document.Find("a[href*='videoreviews.php']").First().Attr("href")
+	if !b {
+		return nil
+	}
+	query, e := url.Parse(link)
+	if e != nil || query.Query().Get("v") == "" {
+		return nil
+	}
+	reviews, e := g.cache.Query(g.languagePage()+fmt.Sprintf(javlibraryReviews, query.Query().Get("v")), g.force)
+	if e != nil {
+		log.Warnw("javlibrary", "reviews", link, "error", e)
+		return nil
+	}
+	var list []*Review
+	reviews.Find("table.review").EachWithBreak(func(i int, selection *goquery.Selection) bool {
+		list = append(list, &Review{
+			User:  strings.TrimSpace(selection.Find("td.userid").Text()),
+			Score: parseScore(selection.Find("td.scores img").AttrOr("title", "")),
+			Date:  strings.TrimSpace(selection.Find("td.date").Text()),
+			Text:  strings.TrimSpace(selection.Find("td.t div.text").Text()),
+		})
+		return len(list) < g.reviews
+	})
+	return list
+}
+
+// GrabJavlibraryOptions ...
+type GrabJavlibraryOptions func(javlibrary *grabJavlibrary)
+
+// JavlibraryLanguage ...
+func JavlibraryLanguage(language GrabLanguage) GrabJavlibraryOptions {
+	return func(javlibrary *grabJavlibrary) {
+		javlibrary.language = language
+	}
+}
+
+// JavlibraryReviews keep the top n user reviews,0 skips the reviews
+func JavlibraryReviews(n int) GrabJavlibraryOptions {
+	return func(javlibrary *grabJavlibrary) {
+		javlibrary.reviews = n
+	}
+}
+
+// NewGrabJavlibrary ...
+func NewGrabJavlibrary(ops ...GrabJavlibraryOptions) IGrab {
+	grab := &grabJavlibrary{
+		mainPage: DefaultJavlibraryMainPage,
+		language: LanguageJapanese,
+		exact:    true,
+		cache:    NewCache(),
+	}
+	for _, op := range ops {
+		op(grab)
+	}
+	return grab
+}
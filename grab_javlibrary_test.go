@@ -0,0 +1,91 @@
+package scrape
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const testJavlibraryDetail = `<html><body>
+<div id="video_title"><h3><a href="/ja/?v=javliabc">ABP-891 The Title</a></h3></div>
+<img id="video_jacket_img" src="//pics.test/abp891pl.jpg">
+<div id="video_id"><table><tr><td class="header">品番:</td><td class="text">ABP-891</td></tr></table></div>
+<div id="video_date"><table><tr><td class="text">2019-09-20</td></tr></table></div>
+<div id="video_length"><table><tr><td><span class="text">120</span>分</td></tr></table></div>
+<div id="video_maker"><table><tr><td class="text">プレステージ</td></tr></table></div>
+<div id="video_review"><table><tr><td><span class="score">(8.50)</span></td></tr></table></div>
+<div id="video_genres"><span class="genre"><a href="vl_genre.php?g=1">単体作品</a></span></div>
+<div id="video_cast"><span class="star"><a href="vl_star.php?s=1">Actor</a></span></div>
+<a href="videoreviews.php?v=javliabc">reviews</a>
+</body></html>`
+
+const testJavlibraryList = `<html><body><div class="videos">
+<div class="video"><a href="./?v=javliabc"><div class="id">ABP-891</div></a></div>
+<div class="video"><a href="./?v=javlidef"><div class="id">ABP-8910</div></a></div>
+</div></body></html>`
+
+const testJavlibraryReviews = `<html><body>
+<table class="review"><tr><td class="userid">user1</td><td class="scores"><img title="9"></td><td class="date">2019-10-01</td><td class="t"><div class="text">good</div></td></tr></table>
+<table class="review"><tr><td class="userid">user2</td><td class="scores"><img title="7"></td><td class="t"><div class="text">ok</div></td></tr></table>
+</body></html>`
+
+const testJavlibraryChallenge = `<html><head><title>Just a moment...</title></head><body><form id="challenge-form"></form></body></html>`
+
+// TestGrabJavlibrary ...
+func TestGrabJavlibrary(t *testing.T) {
+	testCache(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/ja/vl_searchbyid.php" && r.URL.Query().Get("keyword") == "ABP-891":
+			http.Redirect(w, r, "/ja/?v=javliabc", http.StatusFound)
+		case r.URL.Path == "/ja/vl_searchbyid.php" && r.URL.Query().Get("keyword") == "ABP-89":
+			fmt.Fprint(w, testJavlibraryList)
+		case r.URL.Path == "/ja/" && r.URL.Query().Get("v") == "javliabc":
+			fmt.Fprint(w, testJavlibraryDetail)
+		case r.URL.Path == "/ja/videoreviews.php":
+			fmt.Fprint(w, testJavlibraryReviews)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprint(w, testJavlibraryChallenge)
+		}
+	}))
+	defer server.Close()
+
+	grab := NewGrabJavlibrary(JavlibraryReviews(1))
+	grab.MainPage(server.URL)
+	grab.SetForce(true)
+	find, e := grab.Find("abp-891")
+	if e != nil {
+		t.Fatal(e)
+	}
+	contents, _ := find.Result()
+	if len(contents) != 1 {
+		t.Fatal(contents)
+	}
+	c := contents[0]
+	if c.ID != "ABP-891" || c.Title != "The Title" || c.Rating != 8.5 || c.Runtime != 120 || c.Year != "2019" || c.Poster != "https://pics.test/abp891pl.jpg" {
+		t.Fatalf("%+v", c)
+	}
+	if len(c.Actors) != 1 || c.Actors[0].Link != server.URL+"/ja/vl_star.php?s=1" || len(c.Genres) != 1 {
+		t.Fatalf("%+v %+v", c.Actors, c.Genres)
+	}
+	if len(c.Reviews) != 1 || c.Reviews[0].User != "user1" || c.Reviews[0].Score != 9 || c.Reviews[0].Text != "good" {
+		t.Fatalf("%+v", c.Reviews)
+	}
+
+	grab.SetExact(false)
+	find, e = grab.Find("abp-89")
+	if e != nil {
+		t.Fatal(e)
+	}
+	if contents, _ := find.Result(); len(contents) != 1 {
+		t.Fatal("the blocked detail is returned", contents)
+	}
+
+	grab.SetExact(true)
+	if _, e := grab.Find("ssni-001"); !errors.Is(e, ErrBlocked) {
+		t.Fatal(e)
+	}
+}
@@ -0,0 +1,242 @@
+package scrape
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/goextension/log"
+)
+
+// DefaultMgstageMainPage ...
+const DefaultMgstageMainPage = "https://www.mgstage.com"
+const mgstageDetail = "/product/product_detail/%s/"
+const mgstageTimeFormat = "2006/01/02"
+
+var mgstageIDRegexp = regexp.MustCompile(`(?i)^(\d{3,4})?([a-z]{2,8})[-_ ]?(\d{2,5})$`)
+
+// MgstagePrefixes the number prefixes of the amateur labels on mgstage,
+// an id like MIUM-123 is found as 300MIUM-123
+var MgstagePrefixes = map[string]string{
+	"MIUM":  "300",
+	"MAAN":  "300",
+	"NTK":   "300",
+	"LUXU":  "259",
+	"GANA":  "200",
+	"ARA":   "261",
+	"SCUTE": "229",
+	"ORECO": "230",
+	"DCV":   "277",
+	"KIRAY": "314",
+}
+
+func init() {
+	siteCookies["www.mgstage.com"] = []*http.Cookie{
+		{Name: "adc", Value: "1"},
+	}
+}
+
+// mgstageProductID convert the id to the product id of mgstage,like MIUM123 to 300MIUM-123
+func mgstageProductID(id string) string {
+	match := mgstageIDRegexp.FindStringSubmatch(strings.TrimSpace(id))
+	if match == nil {
+		return strings.ToUpper(id)
+	}
+	prefix, label := match[1], strings.ToUpper(match[2])
+	if prefix == "" {
+		prefix = MgstagePrefixes[label]
+	}
+	return prefix + label + "-" + match[3]
+}
+
+// mgstageID the id as it is asked for,the number prefix is only kept when it is given,like MIUM123 to MIUM-123
+func mgstageID(id string) string {
+	match := mgstageIDRegexp.FindStringSubmatch(strings.TrimSpace(id))
+	if match == nil {
+		return strings.ToUpper(id)
+	}
+	return match[1] + strings.ToUpper(match[2]) + "-" + match[3]
+}
+
+type grabMgstage struct {
+	mainPage string
+	language GrabLanguage
+	sample   bool
+	exact    bool
+	details  []*Content
+	cache    *Cache
+	force    bool
+}
+
+// MainPage ...
+func (g *grabMgstage) MainPage(url string) {
+	g.mainPage = url
+}
+
+// SetSample ...
+func (g *grabMgstage) SetSample(b bool) {
+	g.sample = b
+}
+
+// SetExact ...
+func (g *grabMgstage) SetExact(b bool) {
+	g.exact = b
+}
+
+// SetLanguage ...
+func (g *grabMgstage) SetLanguage(language GrabLanguage) {
+	g.language = language
+}
+
+// SetForce ...
+func (g *grabMgstage) SetForce(force bool) {
+	g.force = force
+}
+
+// Name ...
+func (g *grabMgstage) Name() string {
+	return "mgstage"
+}
+
+// HasNext ...
+func (g *grabMgstage) HasNext() bool {
+	return false
+}
+
+// Next ...
+func (g *grabMgstage) Next() (IGrab, error) {
+	return nil, errors.New("mgstage has no next page")
+}
+
+// Result ...
+func (g *grabMgstage) Result() ([]Content, error) {
+	var cs []Content
+	for _, c := range g.details {
+		cs = append(cs, *c)
+	}
+	return cs, nil
+}
+
+func (g *grabMgstage) clone() *grabMgstage {
+	clone := new(grabMgstage)
+	*clone = *g
+	clone.details = nil
+	return clone
+}
+
+// Clone ...
+func (g *grabMgstage) Clone() IGrab {
+	return g.clone()
+}
+
+// Find ...
+func (g *grabMgstage) Find(name string) (IGrab, error) {
+	clone := g.clone()
+	id := mgstageProductID(name)
+	url := clone.mainPage + fmt.Sprintf(mgstageDetail, id)
+	document, e := clone.cache.Query(url, clone.force)
+	if e != nil {
+		return clone, e
+	}
+	title := strings.TrimSpace(document.Find("h1.tag").First().Text())
+	if title == "" {
+		//the age check page or the top page when the product does not exist
+		clone.cache.Delete(url)
+		return clone, errors.New("no data found")
+	}
+	content := &Content{
+		From:          clone.Name(),
+		Language:      clone.language.String(),
+		ID:            mgstageID(name),
+		ProductID:     id,
+		Title:         title,
+		OriginalTitle: title,
+		Poster:        document.Find("a#EnlargeImage").AttrOr("href", ""),
+		Thumb:         document.Find("img.enlarge_image").AttrOr("src", ""),
+		Plot:          strings.TrimSpace(document.Find("#introduction p.txt").First().Text()),
+	}
+	if content.Plot != "" {
+		content.PlotFrom = clone.Name()
+	}
+	document.Find("div.detail_data table tr").Each(func(i int, selection *goquery.Selection) {
+		label := strings.TrimSpace(selection.Find("th").Text())
+		value := selection.Find("td")
+		text := strings.TrimSpace(value.Text())
+		switch {
+		case strings.HasPrefix(label, "出演"):
+			//some amateur movies only show the name in the text
+			value.Find("a").Each(func(i int, selection *goquery.Selection) {
+				link, _ := browseLink(clone.mainPage, selection.AttrOr("href", ""))
+				content.Actors = append(content.Actors, &Star{
+					Name: strings.TrimSpace(selection.Text()),
+					Link: link,
+				})
+			})
+			if len(content.Actors) == 0 && text != "" {
+				content.Actors = append(content.Actors, &Star{Name: text})
+			}
+		case strings.HasPrefix(label, "メーカー"):
+			content.Studio = text
+		case strings.HasPrefix(label, "収録時間"):
+			content.Runtime = parseRuntime(text)
+		case strings.HasPrefix(label, "品番"):
+			content.ProductID = strings.ToUpper(text)
+		case strings.HasPrefix(label, "配信開始日"), strings.HasPrefix(label, "商品発売日"):
+			if !content.ReleaseDate.IsZero() {
+				return
+			}
+			date, e := time.Parse(mgstageTimeFormat, text)
+			if e != nil {
+				log.Warnw("mgstage", "date", text, "error", e)
+				return
+			}
+			content.ReleaseDate = date
+			content.Year = strconv.Itoa(date.Year())
+		case strings.HasPrefix(label, "シリーズ"):
+			content.MovieSet = text
+		case strings.HasPrefix(label, "レーベル"):
+			content.Publisher = text
+		case strings.HasPrefix(label, "ジャンル"):
+			value.Find("a").Each(func(i int, selection *goquery.Selection) {
+				link, _ := browseLink(clone.mainPage, selection.AttrOr("href", ""))
+				content.Genres = append(content.Genres, &Genre{
+					Content: strings.TrimSpace(selection.Text()),
+					URL:     link,
+				})
+			})
+		}
+	})
+	if clone.sample {
+		document.Find("#sample-photo a.sample_image").Each(func(i int, selection *goquery.Selection) {
+			content.Sample = append(content.Sample, &Sample{
+				Index: i,
+				Image: selection.AttrOr("href", ""),
+				Thumb: selection.Find("img").AttrOr("src", ""),
+			})
+		})
+	}
+	clone.details = append(clone.details, content)
+	return clone, nil
+}
+
+// GrabMgstageOptions ...
+type GrabMgstageOptions func(mgstage *grabMgstage)
+
+// NewGrabMgstage ...
+func NewGrabMgstage(ops ...GrabMgstageOptions) IGrab {
+	grab := &grabMgstage{
+		mainPage: DefaultMgstageMainPage,
+		language: LanguageJapanese,
+		exact:    true,
+		cache:    NewCache(),
+	}
+	for _, op := range ops {
+		op(grab)
+	}
+	return grab
+}
@@ -0,0 +1,119 @@
+package scrape
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const testMgstageDetail = `<html><body>
+<h1 class="tag"> The Title </h1>
+<a id="EnlargeImage" href="https://image.test/pb_e_300mium-123.jpg"><img class="enlarge_image" src="https://image.test/pf_o1_300mium-123.jpg"></a>
+<div class="detail_data"><table>
+<tr><th>出演：</th><td>ゆい 20歳</td></tr>
+<tr><th>メーカー：</th><td><a href="/search/?maker[]=1">プレステージプレミアム(PRESTIGE PREMIUM)</a></td></tr>
+<tr><th>収録時間：</th><td>75min</td></tr>
+<tr><th>品番：</th><td>300MIUM-123</td></tr>
+<tr><th>配信開始日：</th><td>2017/10/12</td></tr>
+<tr><th>シリーズ：</th><td>Series</td></tr>
+<tr><th>レーベル：</th><td>Label</td></tr>
+<tr><th>ジャンル：</th><td><a href="/search/?genre[]=1">素人</a><a href="/search/?genre[]=2">美乳</a></td></tr>
+</table></div>
+<div id="introduction"><dd><p class="txt introduction">The plot.</p></dd></div>
+<dl id="sample-photo"><dd><ul><li><a class="sample_image" href="https://image.test/cap_e_0.jpg"><img src="https://image.test/cap_t1_0.jpg"></a></li></ul></dd></dl>
+</body></html>`
+
+// TestMgstageProductID ...
+func TestMgstageProductID(t *testing.T) {
+	for id, product := range map[string]string{
+		"300MIUM-123": "300MIUM-123",
+		"mium123":     "300MIUM-123",
+		"SIRO-1234":   "SIRO-1234",
+		"259LUXU-001": "259LUXU-001",
+	} {
+		if v := mgstageProductID(id); v != product {
+			t.Errorf("id %s: got %s want %s", id, v, product)
+		}
+	}
+	for id, want := range map[string]string{
+		"300MIUM-123": "300MIUM-123",
+		"mium123":     "MIUM-123",
+		"MIUM-123":    "MIUM-123",
+	} {
+		if v := mgstageID(id); v != want {
+			t.Errorf("id %s: got %s want %s", id, v, want)
+		}
+	}
+}
+
+// TestGrabMgstage ...
+func TestGrabMgstage(t *testing.T) {
+	testCache(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != fmt.Sprintf(mgstageDetail, "300MIUM-123") {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, testMgstageDetail)
+	}))
+	defer server.Close()
+
+	grab := NewGrabMgstage()
+	grab.MainPage(server.URL)
+	grab.SetSample(true)
+	grab.SetForce(true)
+	find, e := grab.Find("MIUM-123")
+	if e != nil {
+		t.Fatal(e)
+	}
+	contents, _ := find.Result()
+	if len(contents) != 1 {
+		t.Fatal(contents)
+	}
+	c := contents[0]
+	if c.ID != "MIUM-123" || c.ProductID != "300MIUM-123" || c.Title != "The Title" || c.Runtime != 75 || c.Year != "2017" || c.Plot != "The plot." || c.Publisher != "Label" {
+		t.Fatalf("%+v", c)
+	}
+	if len(c.Actors) != 1 || c.Actors[0].Name != "ゆい 20歳" || len(c.Genres) != 2 || len(c.Sample) != 1 {
+		t.Fatalf("%+v %+v %+v", c.Actors, c.Genres, c.Sample)
+	}
+	for _, id := range []string{"300MIUM-123", "SIRO-1234"} {
+		found := false
+		for _, r := range SelectGrabs(id) {
+			found = found || r.Name == "mgstage"
+		}
+		if !found {
+			t.Errorf("mgstage is not selected for %s", id)
+		}
+	}
+}
+
+// TestServerMgstage ...
+func TestServerMgstage(t *testing.T) {
+	c := testCache(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != fmt.Sprintf(mgstageDetail, "300MIUM-123") {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, testMgstageDetail)
+	}))
+	defer server.Close()
+
+	grab := NewGrabMgstage()
+	grab.MainPage(server.URL)
+	s := NewServer(NewScrape(CacheOption(c), GrabOption(grab), AutoGrabOption(true), SampleOption(false), ForceOption(true)))
+	for _, id := range []string{"MIUM-123", "300MIUM-123"} {
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/movies/"+id, nil))
+		if w.Code != http.StatusOK {
+			t.Fatal(id, w.Code, w.Body.String())
+		}
+		var content Content
+		if e := json.Unmarshal(w.Body.Bytes(), &content); e != nil || content.ID != id || content.ProductID != "300MIUM-123" {
+			t.Fatal(id, content, e)
+		}
+	}
+}
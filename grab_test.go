@@ -8,6 +8,7 @@ import (
 // TestGrabJAVBUS_Find ...
 func TestGrabJAVBUS_Find(t *testing.T) {
 	DebugOn()
+	testClient(t)
 	e := RegisterProxy("http://localhost:7890")
 	if e != nil {
 		return
@@ -44,3 +45,19 @@ func TestStat(t *testing.T) {
 	t.Log(os.IsExist(e1))
 	t.Log(info1, e1)
 }
+
+// TestBrowseLink ...
+func TestBrowseLink(t *testing.T) {
+	if link, b := browseLink(DefaultJavbusMainPage, "/star/okq"); !b || link != DefaultJavbusMainPage+"/star/okq" {
+		t.Fatal(link, b)
+	}
+	if _, b := browseLink(DefaultJavbusMainPage, "name"); b {
+		t.Fatal("name is not a link")
+	}
+	var ids idSet
+	ids.add("abp-891", "ABP-891", " abp-892 ", "")
+	ids.add("abp-892", "abp-893")
+	if len(ids.ids) != 3 || ids.ids[0] != "ABP-891" || ids.ids[1] != "ABP-892" || ids.ids[2] != "ABP-893" {
+		t.Fatal(ids.ids)
+	}
+}
@@ -0,0 +1,468 @@
+package scrape
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/transform"
+)
+
+// DefaultCaribMainPage ...
+const DefaultCaribMainPage = "https://www.caribbeancom.com"
+
+// Default1pondoMainPage ...
+const Default1pondoMainPage = "https://www.1pondo.tv"
+
+// Default10musumeMainPage ...
+const Default10musumeMainPage = "https://www.10musume.com"
+
+// DefaultHeyzoMainPage ...
+const DefaultHeyzoMainPage = "https://www.heyzo.com"
+
+const caribDetail = "/moviepages/%s/index.html"
+const caribImage = "/moviepages/%s/images/l_l.jpg"
+const caribThumb = "/moviepages/%s/images/l_s.jpg"
+const caribTimeFormat = "2006/01/02"
+const d2passDetail = "/dyn/phpauto/movie_details/movie_id/%s.json"
+const d2passGallery = "/dyn/dla/json/movie_gallery/movie_id/%s.json"
+const d2passGalleryImage = "/dyn/dla/images/"
+const heyzoDetail = "/moviepages/%s/index.html"
+const heyzoPoster = "/contents/3000/%s/images/player_thumbnail.jpg"
+const heyzoTimeFormat = "2006-01-02"
+
+var uncensoredDateIDRegexp = regexp.MustCompile(`^(\d{6})([-_])(\d{2,3})$`)
+var heyzoIDRegexp = regexp.MustCompile(`(?i)^HEYZO[-_ ]?(\d{4})$`)
+var isoDurationRegexp = regexp.MustCompile(`^PT(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?$`)
+
+// uncensoredSite an uncensored studio site
+type uncensoredSite struct {
+	name     string
+	mainPage string
+	studio   string
+	patterns []string
+	id       func(id string) (string, bool) //the id of the site
+	detail   func(g *grabUncensored, id string) (*Content, error)
+}
+
+var uncensoredSiteCarib = &uncensoredSite{
+	name:     "caribbeancom",
+	studio:   "カリビアンコム",
+	mainPage: DefaultCaribMainPage,
+	patterns: []string{`^\d{6}-\d{3}$`},
+	id:       uncensoredDateID("-", 3),
+	detail:   caribDetailAnalyze,
+}
+
+var uncensoredSite1pondo = &uncensoredSite{
+	name:     "1pondo",
+	studio:   "一本道",
+	mainPage: Default1pondoMainPage,
+	patterns: []string{`^\d{6}_\d{3}$`},
+	id:       uncensoredDateID("_", 3),
+	detail:   d2passDetailAnalyze,
+}
+
+var uncensoredSite10musume = &uncensoredSite{
+	name:     "10musume",
+	studio:   "天然むすめ",
+	mainPage: Default10musumeMainPage,
+	patterns: []string{`^\d{6}_\d{2}$`},
+	id:       uncensoredDateID("_", 2),
+	detail:   d2passDetailAnalyze,
+}
+
+var uncensoredSiteHeyzo = &uncensoredSite{
+	name:     "heyzo",
+	studio:   "HEYZO",
+	mainPage: DefaultHeyzoMainPage,
+	patterns: []string{`^HEYZO[-_ ]?\d{4}$`},
+	id: func(id string) (string, bool) {
+		match := heyzoIDRegexp.FindStringSubmatch(strings.TrimSpace(id))
+		if match == nil {
+			return "", false
+		}
+		return match[1], true
+	},
+	detail: heyzoDetailAnalyze,
+}
+
+// uncensoredDateID the date ids use a different separator and number size on every site,
+// caribbeancom uses 123120-001 and 1pondo uses 123120_001 for the different movies
+func uncensoredDateID(separator string, size int) func(id string) (string, bool) {
+	return func(id string) (string, bool) {
+		match := uncensoredDateIDRegexp.FindStringSubmatch(strings.TrimSpace(id))
+		if match == nil || match[2] != separator || len(match[3]) != size {
+			return "", false
+		}
+		return match[1] + separator + match[3], true
+	}
+}
+
+type grabUncensored struct {
+	site     *uncensoredSite
+	mainPage string
+	language GrabLanguage
+	sample   bool
+	exact    bool
+	details  []*Content
+	cache    *Cache
+	force    bool
+}
+
+// MainPage ...
+func (g *grabUncensored) MainPage(url string) {
+	g.mainPage = url
+}
+
+// SetSample ...
+func (g *grabUncensored) SetSample(b bool) {
+	g.sample = b
+}
+
+// SetExact ...
+func (g *grabUncensored) SetExact(b bool) {
+	g.exact = b
+}
+
+// SetLanguage ...
+func (g *grabUncensored) SetLanguage(language GrabLanguage) {
+	g.language = language
+}
+
+// SetForce ...
+func (g *grabUncensored) SetForce(force bool) {
+	g.force = force
+}
+
+// Name ...
+func (g *grabUncensored) Name() string {
+	return g.site.name
+}
+
+// HasNext ...
+func (g *grabUncensored) HasNext() bool {
+	return false
+}
+
+// Next ...
+func (g *grabUncensored) Next() (IGrab, error) {
+	return nil, fmt.Errorf("%s has no next page", g.Name())
+}
+
+// Result ...
+func (g *grabUncensored) Result() ([]Content, error) {
+	var cs []Content
+	for _, c := range g.details {
+		cs = append(cs, *c)
+	}
+	return cs, nil
+}
+
+func (g *grabUncensored) clone() *grabUncensored {
+	clone := new(grabUncensored)
+	*clone = *g
+	clone.details = nil
+	return clone
+}
+
+// Clone ...
+func (g *grabUncensored) Clone() IGrab {
+	return g.clone()
+}
+
+// Find ...
+func (g *grabUncensored) Find(name string) (IGrab, error) {
+	clone := g.clone()
+	id, b := clone.site.id(name)
+	if !b {
+		return clone, fmt.Errorf("%s is not a %s id", name, clone.Name())
+	}
+	content, e := clone.site.detail(clone, id)
+	if e != nil {
+		return clone, e
+	}
+	content.From = clone.Name()
+	content.Language = clone.language.String()
+	content.Uncensored = true
+	if content.OriginalTitle == "" {
+		content.OriginalTitle = content.Title
+	}
+	if content.Plot != "" {
+		content.PlotFrom = clone.Name()
+	}
+	if !content.ReleaseDate.IsZero() {
+		content.Year = strconv.Itoa(content.ReleaseDate.Year())
+	}
+	if !clone.sample {
+		content.Sample = nil
+	}
+	clone.details = append(clone.details, content)
+	return clone, nil
+}
+
+// caribDetailAnalyze the pages of caribbeancom are euc-jp encoded
+func caribDetailAnalyze(g *grabUncensored, id string) (*Content, error) {
+	reader, e := g.cache.GetReader(g.mainPage+fmt.Sprintf(caribDetail, id), g.force)
+	if e != nil {
+		return nil, e
+	}
+	document, e := goquery.NewDocumentFromReader(transform.NewReader(reader, japanese.EUCJP.NewDecoder()))
+	if e != nil {
+		return nil, e
+	}
+	title := strings.TrimSpace(document.Find("h1[itemprop='name']").First().Text())
+	if title == "" {
+		return nil, errors.New("no data found")
+	}
+	content := &Content{
+		ID:     id,
+		Title:  title,
+		Plot:   strings.TrimSpace(document.Find("p[itemprop='description']").First().Text()),
+		Studio: g.site.studio,
+		Poster: g.mainPage + fmt.Sprintf(caribImage, id),
+		Thumb:  g.mainPage + fmt.Sprintf(caribThumb, id),
+	}
+	document.Find("li.movie-spec").Each(func(i int, selection *goquery.Selection) {
+		label := strings.TrimSpace(selection.Find("span.spec-title").Text())
+		value := selection.Find("span.spec-content")
+		switch {
+		case strings.HasPrefix(label, "出演"):
+			value.Find("a.spec-item").Each(func(i int, selection *goquery.Selection) {
+				link, _ := browseLink(g.mainPage, selection.AttrOr("href", ""))
+				content.Actors = append(content.Actors, &Star{
+					Name: strings.TrimSpace(selection.Text()),
+					Link: link,
+				})
+			})
+		case strings.HasPrefix(label, "配信日"):
+			content.ReleaseDate, _ = time.Parse(caribTimeFormat, strings.TrimSpace(value.Text()))
+		case strings.HasPrefix(label, "再生時間"):
+			content.Runtime = parseRuntime(value.Text())
+		case strings.HasPrefix(label, "シリーズ"):
+			content.MovieSet = strings.TrimSpace(value.Text())
+		case strings.HasPrefix(label, "タグ"):
+			value.Find("a.spec-item").Each(func(i int, selection *goquery.Selection) {
+				link, _ := browseLink(g.mainPage, selection.AttrOr("href", ""))
+				content.Genres = append(content.Genres, &Genre{
+					Content: strings.TrimSpace(selection.Text()),
+					URL:     link,
+				})
+			})
+		}
+	})
+	//the gallery images for members only are not samples
+	document.Find("div.gallery a[data-is_sample='1']").Each(func(i int, selection *goquery.Selection) {
+		image, b := browseLink(g.mainPage, selection.AttrOr("href", ""))
+		if !b {
+			return
+		}
+		thumb, _ := browseLink(g.mainPage, selection.Find("img").AttrOr("src", ""))
+		content.Sample = append(content.Sample, &Sample{
+			Index: len(content.Sample),
+			Image: image,
+			Thumb: thumb,
+		})
+	})
+	return content, nil
+}
+
+type d2passMovie struct {
+	MovieID     string
+	Title       string
+	Desc        string
+	Release     string
+	Duration    int //seconds
+	ActressesJa []string
+	UCNAME      []string
+	Series      string
+	ThumbHigh   string
+	ThumbUltra  string
+	MovieThumb  string
+	ThumbMed    string
+	SiteID      int
+}
+
+type d2passGalleryList struct {
+	Rows []struct {
+		Img       string
+		Protected bool
+	}
+}
+
+// d2passDetailAnalyze 1pondo and 10musume serve the same json api
+func d2passDetailAnalyze(g *grabUncensored, id string) (*Content, error) {
+	bys, e := g.cache.get(g.mainPage+fmt.Sprintf(d2passDetail, id), !g.force)
+	if e != nil {
+		return nil, e
+	}
+	var movie d2passMovie
+	e = json.Unmarshal(bys, &movie)
+	if e != nil {
+		return nil, fmt.Errorf("%s %s: %w", g.Name(), id, e)
+	}
+	if movie.Title == "" {
+		return nil, errors.New("no data found")
+	}
+	content := &Content{
+		ID:       id,
+		Title:    movie.Title,
+		Plot:     strings.TrimSpace(movie.Desc),
+		Studio:   g.site.studio,
+		MovieSet: movie.Series,
+		Runtime:  (movie.Duration + 30) / 60,
+		Poster:   movie.ThumbUltra,
+		Thumb:    movie.MovieThumb,
+	}
+	if content.Poster == "" {
+		content.Poster = movie.ThumbHigh
+	}
+	content.ReleaseDate, _ = time.Parse(heyzoTimeFormat, movie.Release)
+	for _, name := range movie.ActressesJa {
+		content.Actors = append(content.Actors, &Star{Name: name})
+	}
+	for _, name := range movie.UCNAME {
+		content.Genres = append(content.Genres, &Genre{Content: name})
+	}
+	if !g.sample {
+		return content, nil
+	}
+	bys, e = g.cache.get(g.mainPage+fmt.Sprintf(d2passGallery, id), !g.force)
+	if e != nil {
+		//old movies have no gallery
+		return content, nil
+	}
+	var gallery d2passGalleryList
+	if json.Unmarshal(bys, &gallery) != nil {
+		return content, nil
+	}
+	for _, row := range gallery.Rows {
+		if row.Protected {
+			continue
+		}
+		image := g.mainPage + d2passGalleryImage + row.Img
+		content.Sample = append(content.Sample, &Sample{
+			Index: len(content.Sample),
+			Image: image,
+			Thumb: image,
+		})
+	}
+	return content, nil
+}
+
+type heyzoLinkedData struct {
+	Name        string
+	Image       string
+	Description string
+	DateCreated string
+	Duration    string
+	Actor       struct {
+		Name string
+	}
+}
+
+func heyzoDetailAnalyze(g *grabUncensored, id string) (*Content, error) {
+	document, e := g.cache.Query(g.mainPage+fmt.Sprintf(heyzoDetail, id), g.force)
+	if e != nil {
+		return nil, e
+	}
+	var data heyzoLinkedData
+	_ = json.Unmarshal([]byte(document.Find("script[type='application/ld+json']").First().Text()), &data)
+	title := strings.TrimSpace(document.Find("div#movie h1").First().Text())
+	if title == "" {
+		title = data.Name
+	}
+	if title == "" {
+		return nil, errors.New("no data found")
+	}
+	content := &Content{
+		ID:      "HEYZO-" + id,
+		Title:   title,
+		Plot:    strings.TrimSpace(data.Description),
+		Studio:  g.site.studio,
+		Runtime: isoDuration(data.Duration),
+		Poster:  g.mainPage + fmt.Sprintf(heyzoPoster, id),
+	}
+	content.Thumb = content.Poster
+	if content.Plot == "" {
+		content.Plot = strings.TrimSpace(document.Find("p.memo").First().Text())
+	}
+	document.Find("table.movieInfo tr").Each(func(i int, selection *goquery.Selection) {
+		label := strings.TrimSpace(selection.Find("td").First().Text())
+		value := selection.Find("td").Eq(1)
+		switch {
+		case strings.HasPrefix(label, "公開日"):
+			content.ReleaseDate, _ = time.Parse(heyzoTimeFormat, strings.TrimSpace(value.Text()))
+		case strings.HasPrefix(label, "出演"):
+			value.Find("a").Each(func(i int, selection *goquery.Selection) {
+				link, _ := browseLink(g.mainPage, selection.AttrOr("href", ""))
+				content.Actors = append(content.Actors, &Star{
+					Name: strings.TrimSpace(selection.Text()),
+					Link: link,
+				})
+			})
+		case strings.HasPrefix(label, "シリーズ"):
+			content.MovieSet = strings.Trim(strings.TrimSpace(value.Text()), "-")
+		case strings.HasPrefix(label, "タグ"):
+			value.Find("ul.tag-keyword-list a").Each(func(i int, selection *goquery.Selection) {
+				link, _ := browseLink(g.mainPage, selection.AttrOr("href", ""))
+				content.Genres = append(content.Genres, &Genre{
+					Content: strings.TrimSpace(selection.Text()),
+					URL:     link,
+				})
+			})
+		}
+	})
+	if len(content.Actors) == 0 && data.Actor.Name != "" {
+		content.Actors = append(content.Actors, &Star{Name: data.Actor.Name})
+	}
+	return content, nil
+}
+
+// isoDuration return the minutes of an iso 8601 duration like PT1H2M3S
+func isoDuration(duration string) int {
+	match := isoDurationRegexp.FindStringSubmatch(strings.TrimSpace(duration))
+	if match == nil {
+		return 0
+	}
+	h, _ := strconv.Atoi(match[1])
+	m, _ := strconv.Atoi(match[2])
+	s, _ := strconv.Atoi(match[3])
+	return h*60 + m + (s+30)/60
+}
+
+func newGrabUncensored(site *uncensoredSite) *grabUncensored {
+	return &grabUncensored{
+		site:     site,
+		mainPage: site.mainPage,
+		language: LanguageJapanese,
+		exact:    true,
+		cache:    NewCache(),
+	}
+}
+
+// NewGrabCarib ...
+func NewGrabCarib() IGrab {
+	return newGrabUncensored(uncensoredSiteCarib)
+}
+
+// NewGrab1pondo ...
+func NewGrab1pondo() IGrab {
+	return newGrabUncensored(uncensoredSite1pondo)
+}
+
+// NewGrab10musume ...
+func NewGrab10musume() IGrab {
+	return newGrabUncensored(uncensoredSite10musume)
+}
+
+// NewGrabHeyzo ...
+func NewGrabHeyzo() IGrab {
+	return newGrabUncensored(uncensoredSiteHeyzo)
+}
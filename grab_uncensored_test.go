@@ -0,0 +1,141 @@
+package scrape
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golang.org/x/text/encoding/japanese"
+)
+
+const testCaribDetail = `<html><body>
+<h1 itemprop="name">カリビアン タイトル</h1>
+<p itemprop="description">The plot.</p>
+<ul>
+<li class="movie-spec"><span class="spec-title">出演</span><span class="spec-content"><a class="spec-item" href="/search_act/1/1.html">女優</a></span></li>
+<li class="movie-spec"><span class="spec-title">配信日</span><span class="spec-content">2020/12/31</span></li>
+<li class="movie-spec"><span class="spec-title">再生時間</span><span class="spec-content">01:00:10</span></li>
+<li class="movie-spec"><span class="spec-title">タグ</span><span class="spec-content"><a class="spec-item" href="/listpages/1.htm">タグ</a></span></li>
+</ul>
+<div class="gallery"><a data-is_sample="1" href="/moviepages/123120-001/images/l/1.jpg"><img src="/moviepages/123120-001/images/s/1.jpg"></a><a data-is_sample="0" href="/member/2.jpg"></a></div>
+</body></html>`
+
+const testD2passDetail = `{"MovieID":"123120_001","Title":"タイトル","Desc":"The plot.","Release":"2020-12-31","Duration":3630,"ActressesJa":["女優"],"UCNAME":["タグ"],"ThumbUltra":"https://img.test/ultra.jpg","MovieThumb":"https://img.test/thumb.jpg"}`
+
+const testD2passGallery = `{"Rows":[{"Img":"movie_gallery/123120_001/1.jpg","Protected":false},{"Img":"movie_gallery/123120_001/2.jpg","Protected":true}]}`
+
+const testHeyzoDetail = `<html><head><script type="application/ld+json">{"name":"HEYZO タイトル","description":"The plot.","duration":"PT1H2M40S","actor":{"name":"女優"}}</script></head><body>
+<div id="movie"><h1>HEYZO タイトル</h1></div>
+<table class="movieInfo">
+<tr><td>公開日</td><td> 2020-12-31 </td></tr>
+<tr><td>タグキーワード</td><td><ul class="tag-keyword-list"><li><a href="/search/1">タグ</a></li></ul></td></tr>
+</table>
+</body></html>`
+
+// TestGrabUncensored ...
+func TestGrabUncensored(t *testing.T) {
+	testCache(t)
+	carib, e := japanese.EUCJP.NewEncoder().String(testCaribDetail)
+	if e != nil {
+		t.Fatal(e)
+	}
+	pages := map[string]string{
+		fmt.Sprintf(caribDetail, "123120-001"):   carib,
+		fmt.Sprintf(d2passDetail, "123120_001"):  testD2passDetail,
+		fmt.Sprintf(d2passGallery, "123120_001"): testD2passGallery,
+		fmt.Sprintf(heyzoDetail, "2345"):         testHeyzoDetail,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, b := pages[r.URL.Path]
+		if !b {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, page)
+	}))
+	defer server.Close()
+
+	for _, test := range []struct {
+		grab    IGrab
+		id      string
+		want    string
+		runtime int
+		sample  int
+	}{
+		{NewGrabCarib(), "123120-001", "123120-001", 60, 1},
+		{NewGrab1pondo(), "123120_001", "123120_001", 61, 1},
+		{NewGrabHeyzo(), "heyzo-2345", "HEYZO-2345", 63, 0},
+	} {
+		test.grab.MainPage(server.URL)
+		test.grab.SetSample(true)
+		test.grab.SetForce(true)
+		find, e := test.grab.Find(test.id)
+		if e != nil {
+			t.Fatal(test.grab.Name(), e)
+		}
+		contents, _ := find.Result()
+		if len(contents) != 1 {
+			t.Fatal(test.grab.Name(), contents)
+		}
+		c := contents[0]
+		if c.ID != test.want || !c.Uncensored || c.Plot != "The plot." || c.Year != "2020" || c.Runtime != test.runtime {
+			t.Fatalf("%s: %+v", test.grab.Name(), c)
+		}
+		if len(c.Actors) != 1 || c.Actors[0].Name != "女優" || len(c.Genres) != 1 || c.Genres[0].Content != "タグ" {
+			t.Fatalf("%s: %+v %+v", test.grab.Name(), c.Actors, c.Genres)
+		}
+		if len(c.Sample) != test.sample {
+			t.Fatalf("%s: %+v", test.grab.Name(), c.Sample)
+		}
+	}
+
+	if _, e := NewGrab10musume().Find("123120_001"); e == nil {
+		t.Fatal("10musume ids have 2 numbers")
+	}
+	if _, e := NewGrabCarib().Find("123120_001"); e == nil {
+		t.Fatal("caribbeancom ids are separated by -")
+	}
+	if _, e := NewGrab1pondo().Find("123120-001"); e == nil {
+		t.Fatal("1pondo ids are separated by _")
+	}
+}
+
+// TestRouteUncensored ...
+func TestRouteUncensored(t *testing.T) {
+	testCache(t)
+	for id, want := range map[string]string{
+		"123120-001": "caribbeancom",
+		"123120_001": "1pondo",
+		"123120_01":  "10musume",
+		"HEYZO-2345": "heyzo",
+	} {
+		var sites []string
+		for _, r := range SelectGrabs(id) {
+			if len(r.patterns) != 0 {
+				sites = append(sites, r.Name)
+			}
+		}
+		if len(sites) != 1 || sites[0] != want {
+			t.Errorf("%s: got %v want %s", id, sites, want)
+		}
+	}
+	if sites := SelectGrabs("123120-01"); len(sites) != 0 && len(sites[0].patterns) != 0 {
+		t.Errorf("123120-01 is routed to %s", sites[0].Name)
+	}
+}
+
+// TestRouteUncensoredFile ...
+func TestRouteUncensoredFile(t *testing.T) {
+	testCache(t)
+	for name, want := range map[string]string{
+		"Carib 123120-001.mp4":   "caribbeancom",
+		"1pondo 123120_001.mp4":  "1pondo",
+		"10musume 123120_01.mp4": "10musume",
+	} {
+		selected := SelectGrabs(ParseID(name))
+		if len(selected) == 0 || selected[0].Name != want {
+			t.Errorf("%s: routed to %v want %s", name, selected, want)
+		}
+	}
+}
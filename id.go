@@ -0,0 +1,103 @@
+package scrape
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var idHeyzoRegexp = regexp.MustCompile(`(?i)HEYZO[-_ ]?(?:HD[-_ ]?)?(\d{4})`)
+var idFC2Regexp = regexp.MustCompile(`(?i)FC2[-_ ]?(?:PPV)?[-_ ]?(\d{5,8})`)
+var idDateRegexp = regexp.MustCompile(`(\d{6})([-_])(\d{2,3})`)
+var idNormalRegexp = regexp.MustCompile(`(?i)(\d{0,3}[a-z]{2,8})[-_ ]?(\d{2,5})`)
+
+// idSiteRegexp the site names put before the id,like hhd800.com@
+var idSiteRegexp = regexp.MustCompile(`(?i)[a-z0-9-]+\.(?:com|net|org|cc|tv|me|xyz|la|io|vip)@?`)
+
+// ParseID extract the movie id from a file name like "[xx]abp-891-C.mp4",
+// the site names are skipped and the longest of the other matches is taken
+func ParseID(name string) string {
+	name = filepath.Base(name)
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+	name = idSiteRegexp.ReplaceAllString(name, " ")
+	if match := idHeyzoRegexp.FindStringSubmatch(name); match != nil {
+		return "HEYZO-" + match[1]
+	}
+	if match := idFC2Regexp.FindStringSubmatch(name); match != nil {
+		return "FC2-PPV-" + match[1]
+	}
+	//the separator tells caribbeancom from 1pondo and 10musume
+	if match := idDateRegexp.FindStringSubmatch(name); match != nil {
+		return match[1] + match[2] + match[3]
+	}
+	var id []string
+	for _, match := range idNormalRegexp.FindAllStringSubmatch(name, -1) {
+		if id == nil || len(match[0]) > len(id[0]) {
+			id = match
+		}
+	}
+	if id != nil {
+		return strings.ToUpper(id[1]) + "-" + id[2]
+	}
+	return ""
+}
+
+// IDKind the kind of a movie id
+type IDKind int
+
+// IDKind detail ...
+const (
+	IDUnknown IDKind = iota
+	IDCensored
+	IDFC2
+	IDHeyzo
+	IDDate //caribbeancom,1pondo,10musume like 123120-001 or 123120_01
+	IDAmateur
+)
+
+var idKindStringList = map[IDKind]string{
+	IDUnknown:  "unknown",
+	IDCensored: "censored",
+	IDFC2:      "fc2",
+	IDHeyzo:    "heyzo",
+	IDDate:     "date",
+	IDAmateur:  "amateur",
+}
+
+func (k IDKind) String() string {
+	return idKindStringList[k]
+}
+
+// AmateurPrefixes the prefixes of the amateur ids without a number prefix,like SIRO-1234
+var AmateurPrefixes = []string{"SIRO", "LUXU", "GANA", "MIUM", "MAAN", "ARA", "SCUTE", "KIRAY", "ORECO", "NTK", "DCV", "HOI"}
+
+var idClassifyFC2Regexp = regexp.MustCompile(`(?i)^FC2[-_ ]?(?:PPV)?[-_ ]?\d{5,8}$`)
+var idClassifyHeyzoRegexp = regexp.MustCompile(`(?i)^HEYZO[-_ ]?\d{4}$`)
+var idClassifyDateRegexp = regexp.MustCompile(`^\d{6}[-_]\d{2,3}$`)
+var idClassifyAmateurRegexp = regexp.MustCompile(`(?i)^(\d{3,4})?([a-z]{2,8})[-_]?\d{2,5}$`)
+
+// ClassifyID return the kind of the id
+func ClassifyID(id string) IDKind {
+	id = strings.TrimSpace(id)
+	switch {
+	case idClassifyFC2Regexp.MatchString(id):
+		return IDFC2
+	case idClassifyHeyzoRegexp.MatchString(id):
+		return IDHeyzo
+	case idClassifyDateRegexp.MatchString(id):
+		return IDDate
+	}
+	match := idClassifyAmateurRegexp.FindStringSubmatch(id)
+	if match == nil {
+		return IDUnknown
+	}
+	if match[1] != "" {
+		return IDAmateur
+	}
+	for _, prefix := range AmateurPrefixes {
+		if strings.EqualFold(prefix, match[2]) {
+			return IDAmateur
+		}
+	}
+	return IDCensored
+}
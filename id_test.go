@@ -0,0 +1,42 @@
+package scrape
+
+import "testing"
+
+// TestParseID ...
+func TestParseID(t *testing.T) {
+	for name, id := range map[string]string{
+		"abp-891.mp4":            "ABP-891",
+		"/video/ABP891-C.mkv":    "ABP-891",
+		"FC2-PPV-1234567.mp4":    "FC2-PPV-1234567",
+		"fc2ppv_1234567.mp4":     "FC2-PPV-1234567",
+		"Carib 123120-001.mp4":   "123120-001",
+		"1pondo 123120_001.mp4":  "123120_001",
+		"10musume 123120_01.mp4": "123120_01",
+		"300MIUM-123.mp4":        "300MIUM-123",
+		"readme.txt":             "",
+	} {
+		if v := ParseID(name); v != id {
+			t.Errorf("name %s: got %s want %s", name, v, id)
+		}
+	}
+}
+
+// TestClassifyID ...
+func TestClassifyID(t *testing.T) {
+	for id, kind := range map[string]IDKind{
+		"ABP-891":         IDCensored,
+		"abp891":          IDCensored,
+		"FC2-PPV-1234567": IDFC2,
+		"fc2ppv_1234567":  IDFC2,
+		"HEYZO-2345":      IDHeyzo,
+		"123120-001":      IDDate,
+		"123120_01":       IDDate,
+		"300MIUM-123":     IDAmateur,
+		"SIRO-1234":       IDAmateur,
+		"readme":          IDUnknown,
+	} {
+		if v := ClassifyID(id); v != kind {
+			t.Errorf("id %s: got %s want %s", id, v, kind)
+		}
+	}
+}
@@ -0,0 +1,259 @@
+package scrape
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif" //decode gif
+	"image/jpeg"
+	"image/png"
+	"strings"
+
+	"github.com/goextension/log"
+)
+
+// DefaultPosterRatio the width/height of a dvd jacket
+var DefaultPosterRatio = 0.71
+
+// FaceDetector find the faces of an image,the poster is cropped around the largest one,
+// a real face detector can be plugged in through ImageOption.Detector
+type FaceDetector interface {
+	Detect(img image.Image) []image.Rectangle
+}
+
+// ImageOption the post processing of the output images
+type ImageOption struct {
+	CropPoster  bool         //crop a portrait poster from the right side of the wide cover
+	PosterRatio float64      //width/height of the cropped poster
+	Detector    FaceDetector //nil crops the right side as it is
+	PosterWidth int          //0 keeps the size
+	FanartWidth int
+	ThumbWidth  int
+	Format      string         //jpg or png,empty keeps jpg
+	Quality     int            //jpeg quality
+	Overlay     *OverlayOption //badges drawn on the poster
+}
+
+// DefaultImageOption ...
+func DefaultImageOption() *ImageOption {
+	return &ImageOption{
+		CropPoster:  true,
+		PosterRatio: DefaultPosterRatio,
+		FanartWidth: 0,
+		ThumbWidth:  400,
+		Format:      "jpg",
+		Quality:     90,
+	}
+}
+
+// Ext the file extension of the format
+func (o *ImageOption) Ext() string {
+	if strings.ToLower(o.Format) == "png" {
+		return ".png"
+	}
+	return ".jpg"
+}
+
+type imageProcess struct {
+	name   string
+	crop   bool
+	width  int
+	badges []*Badge
+}
+
+// processImage decode the source,crop and resize it and encode it to the format,
+// the result is cached by the data of the source so reruns are cheap and a changed source is processed again
+func processImage(cache *Cache, source string, option *ImageOption, process imageProcess) ([]byte, error) {
+	bys, e := cache.GetImage(source, false)
+	if e != nil {
+		return nil, e
+	}
+	key := fmt.Sprintf("%x:%s:%t:%g:%T:%d:%s:%d", sha256.Sum256(bys), process.name, process.crop, option.PosterRatio, option.Detector, process.width, option.Ext(), option.Quality)
+	if len(process.badges) != 0 {
+		key += fmt.Sprintf(":%d:%g:%g", option.Overlay.Position, option.Overlay.Size, option.Overlay.Margin)
+		for _, b := range process.badges {
+			key += ":" + b.key()
+		}
+	}
+	if processed, b := cache.getKey(key); b {
+		return processed, nil
+	}
+	img, _, e := image.Decode(bytes.NewReader(bys))
+	if e != nil {
+		return nil, e
+	}
+	if process.crop {
+		img = cropPoster(img, option.PosterRatio, option.Detector)
+	}
+	if process.width > 0 {
+		img = resizeImage(img, process.width)
+	}
+	if len(process.badges) != 0 {
+		img = overlayBadges(img, option.Overlay, process.badges)
+	}
+	buf := bytes.NewBuffer(nil)
+	switch option.Ext() {
+	case ".png":
+		e = png.Encode(buf, img)
+	default:
+		quality := option.Quality
+		if quality <= 0 {
+			quality = jpeg.DefaultQuality
+		}
+		e = jpeg.Encode(buf, img, &jpeg.Options{Quality: quality})
+	}
+	if e != nil {
+		return nil, e
+	}
+	e = cache.setKey(key, buf.Bytes())
+	if e != nil {
+		log.Errorw("process image", "source", source, "error", e)
+	}
+	return buf.Bytes(), nil
+}
+
+// cropPoster crop the right side of a wide cover,centered on the largest face if there is one
+func cropPoster(img image.Image, ratio float64, detector FaceDetector) image.Image {
+	if ratio <= 0 {
+		ratio = DefaultPosterRatio
+	}
+	b := img.Bounds()
+	width := int(float64(b.Dy()) * ratio)
+	if width >= b.Dx() {
+		return img
+	}
+	x := b.Max.X - width
+	if detector != nil {
+		var face image.Rectangle
+		for _, r := range detector.Detect(img) {
+			if r.Dx()*r.Dy() > face.Dx()*face.Dy() {
+				face = r
+			}
+		}
+		if !face.Empty() {
+			x = (face.Min.X+face.Max.X)/2 - width/2
+			if x < b.Min.X {
+				x = b.Min.X
+			}
+			if x+width > b.Max.X {
+				x = b.Max.X - width
+			}
+		}
+	}
+	rect := image.Rect(x, b.Min.Y, x+width, b.Max.Y)
+	dst := image.NewRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, rect.Min, draw.Src)
+	return dst
+}
+
+// resizeImage scale the image to the width with bilinear sampling,the ratio is kept
+func resizeImage(img image.Image, width int) image.Image {
+	b := img.Bounds()
+	if width <= 0 || b.Dx() == 0 || width == b.Dx() {
+		return img
+	}
+	height := b.Dy() * width / b.Dx()
+	if height <= 0 {
+		height = 1
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	sx := float64(b.Dx()) / float64(width)
+	sy := float64(b.Dy()) / float64(height)
+	for y := 0; y < height; y++ {
+		fy := (float64(y)+0.5)*sy - 0.5
+		for x := 0; x < width; x++ {
+			fx := (float64(x)+0.5)*sx - 0.5
+			dst.Set(x, y, bilinear(img, b, fx, fy))
+		}
+	}
+	return dst
+}
+
+func bilinear(img image.Image, b image.Rectangle, fx, fy float64) color.Color {
+	clamp := func(v, max int) int {
+		if v < 0 {
+			return 0
+		}
+		if v >= max {
+			return max - 1
+		}
+		return v
+	}
+	x0, y0 := int(fx), int(fy)
+	if fx < 0 {
+		x0 = -1
+	}
+	if fy < 0 {
+		y0 = -1
+	}
+	dx, dy := fx-float64(x0), fy-float64(y0)
+	var c [4]float64
+	for _, p := range []struct {
+		x, y int
+		w    float64
+	}{
+		{x0, y0, (1 - dx) * (1 - dy)},
+		{x0 + 1, y0, dx * (1 - dy)},
+		{x0, y0 + 1, (1 - dx) * dy},
+		{x0 + 1, y0 + 1, dx * dy},
+	} {
+		r, g, bl, a := img.At(b.Min.X+clamp(p.x, b.Dx()), b.Min.Y+clamp(p.y, b.Dy())).RGBA()
+		c[0] += float64(r) * p.w
+		c[1] += float64(g) * p.w
+		c[2] += float64(bl) * p.w
+		c[3] += float64(a) * p.w
+	}
+	return color.RGBA64{R: uint16(c[0]), G: uint16(c[1]), B: uint16(c[2]), A: uint16(c[3])}
+}
+
+// SkinColumnDetector a skin colour column density heuristic,not a face detector:
+// it samples the right half of the image in 8px columns and returns the column with the most skin colored pixels,
+// covers with a skin colored background or several people may be cropped badly,so it is opt-in
+type SkinColumnDetector struct{}
+
+// Detect ...
+func (SkinColumnDetector) Detect(img image.Image) []image.Rectangle {
+	b := img.Bounds()
+	const cell = 8
+	cols := b.Dx() / cell
+	if cols == 0 || b.Dy() < cell {
+		return nil
+	}
+	density := make([]int, cols)
+	for col := cols / 2; col < cols; col++ {
+		for y := b.Min.Y; y < b.Max.Y; y += cell {
+			r, g, bl, _ := img.At(b.Min.X+col*cell+cell/2, y).RGBA()
+			if isSkin(r>>8, g>>8, bl>>8) {
+				density[col]++
+			}
+		}
+	}
+	best, max := -1, 0
+	for col, d := range density {
+		if d > max {
+			best, max = col, d
+		}
+	}
+	//too few skin pixels to trust
+	if best < 0 || max*cell*4 < b.Dy() {
+		return nil
+	}
+	x := b.Min.X + best*cell
+	return []image.Rectangle{image.Rect(x, b.Min.Y, x+cell, b.Max.Y)}
+}
+
+func isSkin(r, g, b uint32) bool {
+	max, min := r, r
+	for _, v := range []uint32{g, b} {
+		if v > max {
+			max = v
+		}
+		if v < min {
+			min = v
+		}
+	}
+	return r > 95 && g > 40 && b > 20 && max-min > 15 && r > g && r > b && r-g > 15
+}
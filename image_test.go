@@ -0,0 +1,42 @@
+package scrape
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+)
+
+// TestCropPoster ...
+func TestCropPoster(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 800, 538))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: color.RGBA{B: 255, A: 255}}, image.Point{}, draw.Src)
+
+	if DefaultImageOption().Detector != nil {
+		t.Fatal("the detector is opt-in")
+	}
+	poster := cropPoster(img, DefaultPosterRatio, nil)
+	if poster.Bounds().Dx() != 381 || poster.Bounds().Dy() != 538 {
+		t.Fatal(poster.Bounds())
+	}
+
+	//a skin colored area in the middle of the right half
+	draw.Draw(img, image.Rect(560, 100, 620, 400), &image.Uniform{C: color.RGBA{R: 220, G: 170, B: 140, A: 255}}, image.Point{}, draw.Src)
+	faces := SkinColumnDetector{}.Detect(img)
+	if len(faces) != 1 || faces[0].Min.X < 560 || faces[0].Max.X > 620 {
+		t.Fatal(faces)
+	}
+	poster = cropPoster(img, DefaultPosterRatio, SkinColumnDetector{})
+	if poster.Bounds().Dx() != 381 {
+		t.Fatal(poster.Bounds())
+	}
+
+	thumb := resizeImage(img, 400)
+	if thumb.Bounds().Dx() != 400 || thumb.Bounds().Dy() != 269 {
+		t.Fatal(thumb.Bounds())
+	}
+	r, g, b, _ := thumb.At(295, 125).RGBA()
+	if !isSkin(r>>8, g>>8, b>>8) {
+		t.Fatal("resized color is wrong", r>>8, g>>8, b>>8)
+	}
+}
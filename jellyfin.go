@@ -0,0 +1,230 @@
+package scrape
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/goextension/log"
+)
+
+// JellyfinProviderName the provider id key of the results
+var JellyfinProviderName = "GoScrape"
+
+// JellyfinSearchResult ...
+type JellyfinSearchResult struct {
+	Name               string
+	ProviderIds        map[string]string
+	ProductionYear     int
+	PremiereDate       string
+	ImageUrl           string
+	Overview           string
+	SearchProviderName string
+}
+
+// JellyfinPerson ...
+type JellyfinPerson struct {
+	Name     string
+	Role     string
+	Type     string
+	ImageUrl string
+}
+
+// JellyfinMovie ...
+type JellyfinMovie struct {
+	Name           string
+	OriginalTitle  string
+	Overview       string
+	PremiereDate   string
+	ProductionYear int
+	Studios        []string
+	Genres         []string
+	Tags           []string
+	CollectionName string
+	People         []*JellyfinPerson
+	ProviderIds    map[string]string
+}
+
+// JellyfinImage ...
+type JellyfinImage struct {
+	Url          string
+	Type         string
+	ProviderName string
+}
+
+// ServerJellyfin serve the jellyfin provider contract under /jellyfin/
+func ServerJellyfin(b bool) ServerOptions {
+	return func(s *Server) {
+		s.jellyfin = b
+	}
+}
+
+func (s *Server) registerJellyfin() {
+	s.mux.HandleFunc("/jellyfin/search", s.handleJellyfinSearch)
+	s.mux.HandleFunc("/jellyfin/movies/", s.handleJellyfinMovie)
+}
+
+// handleJellyfinSearch search by ?id= or ?name=,a name that contains an id only returns that id
+func (s *Server) handleJellyfinSearch(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("id")
+	name := r.URL.Query().Get("name")
+	if id == "" {
+		id = ParseID(name)
+	}
+	var results []*JellyfinSearchResult
+	if id != "" {
+		content, e := s.Movie(id)
+		if e != nil && !errors.Is(e, ErrNotFound) {
+			serverError(w, serverStatus(e), e)
+			return
+		}
+		if content != nil {
+			results = append(results, s.jellyfinSearchResult(r, content))
+		}
+	} else if name != "" {
+		for content := range s.scrape.Search(r.Context(), name, DefaultServerSearchLimit) {
+			c := content
+			//the search does not cache the images,the thumb is only linked when it is cached here
+			if c.Thumb != "" {
+				if _, e := s.scrape.Cache().GetImage(c.Thumb, false); e != nil {
+					log.Warnw("jellyfin", "thumb", c.Thumb, "error", e)
+					c.Thumb = ""
+				}
+			}
+			results = append(results, s.jellyfinSearchResult(r, &c))
+		}
+	} else {
+		serverError(w, http.StatusBadRequest, errors.New("name and id are empty"))
+		return
+	}
+	if results == nil {
+		results = []*JellyfinSearchResult{}
+	}
+	s.writeCached(w, r, results)
+}
+
+// handleJellyfinMovie serve /jellyfin/movies/{id} and /jellyfin/movies/{id}/images
+func (s *Server) handleJellyfinMovie(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/jellyfin/movies/")
+	images := strings.HasSuffix(path, "/images")
+	id := strings.TrimSuffix(path, "/images")
+	if id == "" || strings.Contains(id, "/") {
+		serverError(w, http.StatusNotFound, ErrNotFound)
+		return
+	}
+	content, e := s.Movie(id)
+	if e != nil {
+		serverError(w, serverStatus(e), e)
+		return
+	}
+	if images {
+		s.writeCached(w, r, s.jellyfinImages(r, content))
+		return
+	}
+	s.writeCached(w, r, s.jellyfinMovie(r, content))
+}
+
+func (s *Server) jellyfinSearchResult(r *http.Request, content *Content) *JellyfinSearchResult {
+	return &JellyfinSearchResult{
+		Name:               content.ID + " " + content.Title,
+		ProviderIds:        map[string]string{JellyfinProviderName: content.ID},
+		ProductionYear:     jellyfinYear(content),
+		PremiereDate:       jellyfinDate(content),
+		ImageUrl:           serverImageURL(r, content.Thumb),
+		Overview:           content.Plot,
+		SearchProviderName: JellyfinProviderName,
+	}
+}
+
+func (s *Server) jellyfinMovie(r *http.Request, content *Content) *JellyfinMovie {
+	movie := &JellyfinMovie{
+		Name:           content.ID + " " + content.Title,
+		OriginalTitle:  content.OriginalTitle,
+		Overview:       content.Plot,
+		PremiereDate:   jellyfinDate(content),
+		ProductionYear: jellyfinYear(content),
+		CollectionName: content.MovieSet,
+		ProviderIds:    map[string]string{JellyfinProviderName: content.ID},
+		Studios:        []string{},
+		Genres:         []string{},
+		Tags:           []string{},
+		People:         []*JellyfinPerson{},
+	}
+	for _, studio := range []string{content.Studio, content.Publisher} {
+		if studio != "" {
+			movie.Studios = append(movie.Studios, studio)
+		}
+	}
+	for _, g := range content.Genres {
+		movie.Genres = append(movie.Genres, g.Content)
+	}
+	if content.Uncensored {
+		movie.Tags = append(movie.Tags, "uncensored")
+	}
+	for _, actor := range content.Actors {
+		movie.People = append(movie.People, &JellyfinPerson{
+			Name:     actor.Name,
+			Role:     "Actor",
+			Type:     "Actor",
+			ImageUrl: serverImageURL(r, actor.Image),
+		})
+	}
+	if content.Director != "" {
+		movie.People = append(movie.People, &JellyfinPerson{
+			Name: content.Director,
+			Role: "Director",
+			Type: "Director",
+		})
+	}
+	return movie
+}
+
+func (s *Server) jellyfinImages(r *http.Request, content *Content) []*JellyfinImage {
+	images := []*JellyfinImage{}
+	add := func(source, t string) {
+		if source == "" {
+			return
+		}
+		images = append(images, &JellyfinImage{
+			Url:          serverImageURL(r, source),
+			Type:         t,
+			ProviderName: JellyfinProviderName,
+		})
+	}
+	add(content.Poster, "Primary")
+	add(content.Poster, "Backdrop")
+	add(content.Thumb, "Thumb")
+	for _, sample := range content.Sample {
+		add(sample.Image, "Backdrop")
+	}
+	return images
+}
+
+func jellyfinYear(content *Content) int {
+	if content.ReleaseDate.IsZero() {
+		return 0
+	}
+	return content.ReleaseDate.Year()
+}
+
+func jellyfinDate(content *Content) string {
+	if content.ReleaseDate.IsZero() {
+		return ""
+	}
+	return content.ReleaseDate.Format("2006-01-02")
+}
+
+// serverImageURL the url of the cached image served by /images/
+func serverImageURL(r *http.Request, source string) string {
+	if source == "" {
+		return ""
+	}
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	return scheme + "://" + r.Host + "/images/" + Hash(source)
+}
@@ -0,0 +1,306 @@
+package scrape
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/dgraph-io/badger/v3"
+	"github.com/goextension/log"
+)
+
+// DefaultLibraryPath ...
+var DefaultLibraryPath = "library"
+
+const libraryPrefix = "content:"
+
+// LibraryRecord a scraped title in the library
+type LibraryRecord struct {
+	ID      string
+	Content *Content            //merged content
+	Sources map[string]*Content //raw content,key is where it comes from
+	Paths   []string            //files of this title
+	Scraped time.Time
+}
+
+// LibraryFilter ...
+type LibraryFilter func(record *LibraryRecord) bool
+
+// Library persistent store of the scraped titles
+type Library struct {
+	db *badger.DB
+}
+
+// OpenLibrary ...
+func OpenLibrary(path string) (*Library, error) {
+	db, e := badger.Open(badger.DefaultOptions(path).WithLogger(nil))
+	if e != nil {
+		return nil, e
+	}
+	return &Library{db: db}, nil
+}
+
+// LibraryOption save the results of every Find to the library
+func LibraryOption(library *Library) Options {
+	return func(impl *scrapeImpl) {
+		impl.library = library
+	}
+}
+
+// Close ...
+func (l *Library) Close() error {
+	return l.db.Close()
+}
+
+func libraryKey(id string) []byte {
+	return []byte(libraryPrefix + strings.ToUpper(id))
+}
+
+// Get ...
+func (l *Library) Get(id string) (*LibraryRecord, error) {
+	var record *LibraryRecord
+	e := l.db.View(func(txn *badger.Txn) error {
+		item, e := txn.Get(libraryKey(id))
+		if e != nil {
+			if errors.Is(e, badger.ErrKeyNotFound) {
+				return ErrNotFound
+			}
+			return e
+		}
+		return item.Value(func(val []byte) error {
+			record = new(LibraryRecord)
+			return json.Unmarshal(val, record)
+		})
+	})
+	if e != nil {
+		return nil, e
+	}
+	return record, nil
+}
+
+// Put ...
+func (l *Library) Put(record *LibraryRecord) error {
+	record.ID = strings.ToUpper(record.ID)
+	bys, e := json.Marshal(record)
+	if e != nil {
+		return e
+	}
+	return l.db.Update(func(txn *badger.Txn) error {
+		return txn.Set(libraryKey(record.ID), bys)
+	})
+}
+
+// Delete ...
+func (l *Library) Delete(id string) error {
+	return l.db.Update(func(txn *badger.Txn) error {
+		return txn.Delete(libraryKey(id))
+	})
+}
+
+// update read,change and write the record in one transaction,the record is nil when it is not saved yet,
+// the conflicting transactions of the other goroutines are tried again
+func (l *Library) update(id string, f func(record *LibraryRecord) (*LibraryRecord, error)) error {
+	for {
+		e := l.db.Update(func(txn *badger.Txn) error {
+			var record *LibraryRecord
+			item, e := txn.Get(libraryKey(id))
+			switch {
+			case e == nil:
+				e = item.Value(func(val []byte) error {
+					record = new(LibraryRecord)
+					return json.Unmarshal(val, record)
+				})
+				if e != nil {
+					return e
+				}
+			case !errors.Is(e, badger.ErrKeyNotFound):
+				return e
+			}
+			record, e = f(record)
+			if e != nil {
+				return e
+			}
+			record.ID = strings.ToUpper(record.ID)
+			bys, e := json.Marshal(record)
+			if e != nil {
+				return e
+			}
+			return txn.Set(libraryKey(record.ID), bys)
+		})
+		if !errors.Is(e, badger.ErrConflict) {
+			return e
+		}
+	}
+}
+
+// Save merge the contents and save them with the scrape time,the existing paths are kept
+func (l *Library) Save(id string, contents []Content) error {
+	sources := make(map[string]*Content, len(contents))
+	var list []*Content
+	for i := range contents {
+		//the merge changes the first content,the sources keep their own copies
+		source, c := contents[i], contents[i]
+		sources[source.From] = &source
+		list = append(list, &c)
+	}
+	merged := MergeOptimize(id, list)
+	if merged == nil {
+		return ErrNotFound
+	}
+	return l.update(id, func(record *LibraryRecord) (*LibraryRecord, error) {
+		if record == nil {
+			record = &LibraryRecord{ID: id}
+		}
+		record.Sources = sources
+		record.Content = merged
+		record.Scraped = time.Now()
+		return record, nil
+	})
+}
+
+// AddPath map a file to the title
+func (l *Library) AddPath(id string, path string) error {
+	return l.update(id, func(record *LibraryRecord) (*LibraryRecord, error) {
+		if record == nil {
+			return nil, ErrNotFound
+		}
+		for _, p := range record.Paths {
+			if p == path {
+				return record, nil
+			}
+		}
+		record.Paths = append(record.Paths, path)
+		return record, nil
+	})
+}
+
+// Range ...
+func (l *Library) Range(f func(record *LibraryRecord) error) error {
+	return l.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+		prefix := []byte(libraryPrefix)
+		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+			record := new(LibraryRecord)
+			e := it.Item().Value(func(val []byte) error {
+				return json.Unmarshal(val, record)
+			})
+			if e != nil {
+				return e
+			}
+			e = f(record)
+			if e != nil {
+				return e
+			}
+		}
+		return nil
+	})
+}
+
+// Query return the records matched every filter
+func (l *Library) Query(filters ...LibraryFilter) ([]*LibraryRecord, error) {
+	var records []*LibraryRecord
+	e := l.Range(func(record *LibraryRecord) error {
+		for _, f := range filters {
+			if !f(record) {
+				return nil
+			}
+		}
+		records = append(records, record)
+		return nil
+	})
+	return records, e
+}
+
+// ByActor ...
+func (l *Library) ByActor(name string) ([]*LibraryRecord, error) {
+	return l.Query(LibraryActor(name))
+}
+
+// ByStudio ...
+func (l *Library) ByStudio(studio string) ([]*LibraryRecord, error) {
+	return l.Query(LibraryStudio(studio))
+}
+
+// BySeries ...
+func (l *Library) BySeries(series string) ([]*LibraryRecord, error) {
+	return l.Query(LibrarySeries(series))
+}
+
+// ByGenre ...
+func (l *Library) ByGenre(genre string) ([]*LibraryRecord, error) {
+	return l.Query(LibraryGenre(genre))
+}
+
+// ByDate return the records released in [from,to],a zero time is not limited
+func (l *Library) ByDate(from, to time.Time) ([]*LibraryRecord, error) {
+	return l.Query(LibraryDate(from, to))
+}
+
+// LibraryActor ...
+func LibraryActor(name string) LibraryFilter {
+	return func(record *LibraryRecord) bool {
+		for _, actor := range record.Content.Actors {
+			if strings.EqualFold(actor.Name, name) {
+				return true
+			}
+			for _, alias := range actor.Alias {
+				if strings.EqualFold(alias, name) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+}
+
+// LibraryStudio ...
+func LibraryStudio(studio string) LibraryFilter {
+	return func(record *LibraryRecord) bool {
+		return strings.EqualFold(record.Content.Studio, studio)
+	}
+}
+
+// LibrarySeries ...
+func LibrarySeries(series string) LibraryFilter {
+	return func(record *LibraryRecord) bool {
+		return strings.EqualFold(record.Content.MovieSet, series)
+	}
+}
+
+// LibraryGenre ...
+func LibraryGenre(genre string) LibraryFilter {
+	return func(record *LibraryRecord) bool {
+		for _, g := range record.Content.Genres {
+			if strings.EqualFold(g.Content, genre) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// LibraryDate ...
+func LibraryDate(from, to time.Time) LibraryFilter {
+	return func(record *LibraryRecord) bool {
+		date := record.Content.ReleaseDate
+		if !from.IsZero() && date.Before(from) {
+			return false
+		}
+		if !to.IsZero() && date.After(to) {
+			return false
+		}
+		return true
+	}
+}
+
+// librarySave save every found id of the scrape
+func librarySave(library *Library, contents map[string][]Content) {
+	for id, cs := range contents {
+		e := library.Save(id, cs)
+		if e != nil {
+			log.Errorw("library", "id", id, "error", e)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package scrape
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestLibrary ...
+func TestLibrary(t *testing.T) {
+	dir, e := ioutil.TempDir("", "library")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	library, e := OpenLibrary(dir)
+	if e != nil {
+		t.Fatal(e)
+	}
+	date := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)
+	e = library.Save("ABP-891", []Content{
+		{From: "javbus", ID: "ABP-891", Studio: "Prestige", ReleaseDate: date, Actors: []*Star{{Name: "a"}}},
+		{From: "javdb", ID: "ABP-891", Genres: []*Genre{{Content: "g"}}, Actors: []*Star{{Name: "a"}, {Name: "b"}}},
+	})
+	if e != nil {
+		t.Fatal(e)
+	}
+	if e = library.AddPath("abp-891", "/video/abp-891.mp4"); e != nil {
+		t.Fatal(e)
+	}
+	if e = library.Close(); e != nil {
+		t.Fatal(e)
+	}
+
+	library, e = OpenLibrary(dir)
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer library.Close()
+	record, e := library.Get("abp-891")
+	if e != nil {
+		t.Fatal(e)
+	}
+	if len(record.Sources) != 2 || len(record.Paths) != 1 || len(record.Content.Actors) != 2 || len(record.Content.Genres) != 1 {
+		t.Fatalf("%+v", record)
+	}
+	//the sources are kept as they are found
+	if javbus := record.Sources["javbus"]; javbus == nil || len(javbus.Actors) != 1 || len(javbus.Genres) != 0 || javbus.Studio != "Prestige" {
+		t.Fatalf("%+v", javbus)
+	}
+	if javdb := record.Sources["javdb"]; javdb == nil || len(javdb.Actors) != 2 || len(javdb.Genres) != 1 || javdb.Studio != "" {
+		t.Fatalf("%+v", javdb)
+	}
+	for name, query := range map[string]func() ([]*LibraryRecord, error){
+		"actor":  func() ([]*LibraryRecord, error) { return library.ByActor("B") },
+		"studio": func() ([]*LibraryRecord, error) { return library.ByStudio("prestige") },
+		"genre":  func() ([]*LibraryRecord, error) { return library.ByGenre("g") },
+		"date":   func() ([]*LibraryRecord, error) { return library.ByDate(date, time.Time{}) },
+	} {
+		records, e := query()
+		if e != nil || len(records) != 1 {
+			t.Errorf("query %s: %v %v", name, records, e)
+		}
+	}
+	if records, _ := library.BySeries("none"); len(records) != 0 {
+		t.Error(records)
+	}
+
+	//the paths added at the same time are all kept
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if e := library.AddPath("ABP-891", fmt.Sprintf("/video/%d.mp4", i)); e != nil {
+				t.Error(e)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if record, e = library.Get("ABP-891"); e != nil || len(record.Paths) != 9 {
+		t.Fatal(record, e)
+	}
+	if e := library.AddPath("XYZ-001", "/video/xyz.mp4"); !errors.Is(e, ErrNotFound) {
+		t.Fatal(e)
+	}
+}
@@ -0,0 +1,75 @@
+package scrape
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const nfoTimeFormat = "2006-01-02"
+
+// nfoMovie the fields of a kodi/jellyfin movie nfo that can be locked
+type nfoMovie struct {
+	XMLName       xml.Name `xml:"movie"`
+	Title         string   `xml:"title"`
+	OriginalTitle string   `xml:"originaltitle"`
+	Plot          string   `xml:"plot"`
+	Year          string   `xml:"year"`
+	Premiered     string   `xml:"premiered"`
+	Runtime       string   `xml:"runtime"`
+	Studio        string   `xml:"studio"`
+	Director      string   `xml:"director"`
+	Genres        []string `xml:"genre"`
+	Actors        []struct {
+		Name  string `xml:"name"`
+		Thumb string `xml:"thumb"`
+	} `xml:"actor"`
+	LockData     bool   `xml:"lockdata"`
+	LockedFields string `xml:"lockedfields"`
+}
+
+// readNfo read a movie nfo written by kodi or jellyfin,
+// the fields in <lockedfields>(separated by |) are locked and <lockdata> locks all
+func readNfo(path string) (*Content, error) {
+	bys, e := ioutil.ReadFile(path)
+	if e != nil {
+		return nil, e
+	}
+	var nfo nfoMovie
+	e = xml.Unmarshal(bys, &nfo)
+	if e != nil {
+		return nil, e
+	}
+	content := &Content{
+		Title:         strings.TrimSpace(nfo.Title),
+		OriginalTitle: strings.TrimSpace(nfo.OriginalTitle),
+		Plot:          strings.TrimSpace(nfo.Plot),
+		Year:          strings.TrimSpace(nfo.Year),
+		Studio:        strings.TrimSpace(nfo.Studio),
+		Director:      strings.TrimSpace(nfo.Director),
+	}
+	content.Runtime, _ = strconv.Atoi(strings.TrimSpace(nfo.Runtime))
+	if date, e := time.Parse(nfoTimeFormat, strings.TrimSpace(nfo.Premiered)); e == nil {
+		content.ReleaseDate = date
+	}
+	for _, genre := range nfo.Genres {
+		content.Genres = append(content.Genres, &Genre{Content: strings.TrimSpace(genre)})
+	}
+	for _, actor := range nfo.Actors {
+		content.Actors = append(content.Actors, &Star{
+			Name:  strings.TrimSpace(actor.Name),
+			Image: strings.TrimSpace(actor.Thumb),
+		})
+	}
+	for _, field := range strings.Split(nfo.LockedFields, "|") {
+		if field = strings.TrimSpace(field); field != "" {
+			content.Locked = append(content.Locked, field)
+		}
+	}
+	if nfo.LockData {
+		content.Locked = append(content.Locked, "*")
+	}
+	return content, nil
+}
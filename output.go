@@ -1,12 +1,12 @@
 package scrape
 
 import (
-	"encoding/json"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/goextension/log"
 )
@@ -16,6 +16,7 @@ var DefaultOutputPath = "image"
 
 type OutputInfo struct {
 	Name        string
+	SourceFile  string //the video file,used by the poster badges
 	Skip        bool
 	Force       bool
 	OutputPath  string
@@ -34,6 +35,11 @@ type OutputInfo struct {
 	SampleFiles []string
 	ImagePath   string
 	InfoExt     string
+	Policy      MergePolicy
+	CopyFanart  bool
+	FanartPath  string
+	FanartName  string
+	Image       *ImageOption //nil copies the images as they are
 }
 
 func DefaultOutputOption() *OutputInfo {
@@ -45,6 +51,10 @@ func DefaultOutputOption() *OutputInfo {
 		InfoPath:   "",
 		InfoName:   "",
 		InfoExt:    ".nfo",
+		Policy:     PolicyFillEmpty,
+		CopyFanart: false,
+		FanartPath: "",
+		FanartName: "fanart",
 		CopyPoster: true,
 		PosterPath: "",
 		PosterName: "poster",
@@ -88,24 +98,45 @@ func copyCache(cache *Cache, msg *Content, sample bool, output string, force boo
 	return nil
 }
 
-func copyInfo(msg *Content, path string, name string) error {
+// copyInfo write the info file,an existing one is merged with the policy
+func copyInfo(msg *Content, path string, name string, policy MergePolicy) error {
 	inf := filepath.Join(path, name)
 	_ = os.MkdirAll(filepath.Dir(inf), os.ModePerm)
 	info, e := os.Stat(inf)
 	if e != nil && !os.IsNotExist(e) {
 		return e
 	}
+	content := *msg
+	content.Scraped = time.Now()
+	content.Checksums = contentChecksums(msg)
 	if e == nil && info.Size() != 0 {
-		return nil
-	}
-	bytes, e := json.MarshalIndent(msg, "", " ")
-	if e != nil {
-		return e
+		old, e := readInfo(inf)
+		if e != nil {
+			//a nfo written by kodi or jellyfin is owned by the media server and kept as it is
+			if nfo, e := readNfo(inf); e == nil {
+				log.Infow("copy info", "path", inf, "keep", "media server nfo", "locked", nfo.Locked)
+				return nil
+			}
+			log.Warnw("copy info", "path", inf, "keep", "not an info file", "error", e)
+			return nil
+		}
+		if old.ID == "" {
+			old.ID = msg.ID
+		}
+		merged := ApplyPolicy(policy, old, &content)
+		if len(DiffContent(old, merged)) == 0 {
+			return nil
+		}
+		if debug {
+			log.Infow("copy info", "path", inf, "policy", policy, "diff", Diff(old, merged).String())
+		}
+		content = *merged
 	}
-	return ioutil.WriteFile(inf, bytes, 0755)
+	return writeInfo(&content, inf)
 }
 
-func copyFileWithInfo(cache *Cache, content Content, option *OutputInfo) error {
+// copyFileWithInfo output the content,a failed image is retried with the same image of the others
+func copyFileWithInfo(cache *Cache, content Content, option *OutputInfo, others ...Content) error {
 	var e error
 	if option.Skip {
 		return nil
@@ -114,14 +145,14 @@ func copyFileWithInfo(cache *Cache, content Content, option *OutputInfo) error {
 		if option.InfoName == "@" {
 			option.InfoName = content.ID
 		}
-		e = copyInfo(&content, filepath.Join(option.OutputPath, option.InfoPath), option.InfoName+option.InfoExt)
+		e = copyInfo(&content, filepath.Join(option.OutputPath, option.InfoPath), option.InfoName+option.InfoExt, option.Policy)
 		if e != nil {
 			log.Errorw("OutputCallback", "error", e, "output", content.ID)
 		}
 	}
 
 	if option.CopyPoster {
-		option.PosterName = option.PosterName + Ext(content.Poster)
+		option.PosterName = option.PosterName + outputExt(option, content.Poster)
 		if option.PosterPath == "" {
 			option.PosterPath = filepath.Join(option.ImagePath, option.PosterPath)
 		}
@@ -129,19 +160,75 @@ func copyFileWithInfo(cache *Cache, content Content, option *OutputInfo) error {
 		if debug {
 			log.Infow("CopyFile", "source", content.Poster, "path", path)
 		}
-		e = copyFile(cache, content.Poster, path, option.Force)
+		if option.Image != nil {
+			process := imageProcess{
+				name:  "poster",
+				crop:  option.Image.CropPoster,
+				width: option.Image.PosterWidth,
+			}
+			if option.Image.Overlay != nil {
+				process.badges = option.Image.Overlay.active(&content, option.SourceFile)
+			}
+			e = copyFirst(imageSources(content, others, posterSource), func(source string) error {
+				return copyImage(cache, source, path, option.Force, option.Image, process)
+			})
+		} else {
+			e = copyFirst(imageSources(content, others, posterSource), func(source string) error {
+				return copyFile(cache, source, path, option.Force)
+			})
+		}
+		if e != nil {
+			log.Errorw("OutputCallback", "error", e, "output", content.ID)
+		}
+	}
+
+	if option.CopyFanart {
+		option.FanartName = option.FanartName + outputExt(option, content.Poster)
+		if option.FanartPath == "" {
+			option.FanartPath = filepath.Join(option.ImagePath, option.FanartPath)
+		}
+		path := filepath.Join(option.OutputPath, option.FanartPath, option.FanartName)
+		if option.Image != nil {
+			e = copyFirst(imageSources(content, others, posterSource), func(source string) error {
+				return copyImage(cache, source, path, option.Force, option.Image, imageProcess{
+					name:  "fanart",
+					width: option.Image.FanartWidth,
+				})
+			})
+		} else {
+			e = copyFirst(imageSources(content, others, posterSource), func(source string) error {
+				return copyFile(cache, source, path, option.Force)
+			})
+		}
 		if e != nil {
 			log.Errorw("OutputCallback", "error", e, "output", content.ID)
 		}
 	}
 
 	if option.CopyThumb {
-		option.ThumbName = option.ThumbName + Ext(content.Thumb)
+		get := thumbSource
+		//the thumb is a small search tile,a resized cover is better
+		if option.Image != nil && option.Image.ThumbWidth > 0 && content.Poster != "" {
+			get = posterSource
+		}
+		sources := imageSources(content, others, get)
+		option.ThumbName = option.ThumbName + outputExt(option, get(&content))
 		if option.ThumbPath == "" {
 			option.ThumbPath = filepath.Join(option.ImagePath, option.ThumbPath)
 		}
 		path := filepath.Join(option.OutputPath, option.ThumbPath, option.ThumbName)
-		e = copyFile(cache, content.Thumb, path, option.Force)
+		if option.Image != nil {
+			e = copyFirst(sources, func(source string) error {
+				return copyImage(cache, source, path, option.Force, option.Image, imageProcess{
+					name:  "thumb",
+					width: option.Image.ThumbWidth,
+				})
+			})
+		} else {
+			e = copyFirst(sources, func(source string) error {
+				return copyFile(cache, source, path, option.Force)
+			})
+		}
 		if e != nil {
 			log.Errorw("OutputCallback", "error", e, "output", content.ID)
 		}
@@ -164,6 +251,61 @@ func copyFileWithInfo(cache *Cache, content Content, option *OutputInfo) error {
 }
 
 func copyFile(cache *Cache, source, path string, force bool) error {
+	return writeFile(source, path, force, func() ([]byte, error) {
+		return cache.GetImage(source, force)
+	})
+}
+
+func posterSource(c *Content) string {
+	return c.Poster
+}
+
+func thumbSource(c *Content) string {
+	return c.Thumb
+}
+
+// imageSources the image of the content first and then the different ones of the others
+func imageSources(content Content, others []Content, get func(c *Content) string) []string {
+	var sources []string
+	add := func(source string) {
+		if source == "" {
+			return
+		}
+		for _, v := range sources {
+			if v == source {
+				return
+			}
+		}
+		sources = append(sources, source)
+	}
+	add(get(&content))
+	for i := range others {
+		add(get(&others[i]))
+	}
+	return sources
+}
+
+// copyFirst copy the sources in order until one succeeds
+func copyFirst(sources []string, f func(source string) error) (e error) {
+	for _, source := range sources {
+		e = f(source)
+		if e == nil {
+			return nil
+		}
+		log.Warnw("copy", "source", source, "next", true, "error", e)
+	}
+	return e
+}
+
+// copyImage write the processed image of the source
+func copyImage(cache *Cache, source, path string, force bool, option *ImageOption, process imageProcess) error {
+	return writeFile(source, path, force, func() ([]byte, error) {
+		return processImage(cache, source, option, process)
+	})
+}
+
+// writeFile write the data to path,an existing file is only overwritten with force
+func writeFile(source, path string, force bool, get func() ([]byte, error)) error {
 	if source == "" {
 		return nil
 	}
@@ -172,20 +314,16 @@ func copyFile(cache *Cache, source, path string, force bool) error {
 		log.Infow("CopyFile", "source", source, "path", path)
 	}
 	_ = os.MkdirAll(filepath.Dir(path), os.ModePerm)
-	info, e := os.Stat(path)
-
-	var bys []byte
-	if force {
-		bys, e = cache.ForceGet(source)
-	} else {
+	if !force {
+		info, e := os.Stat(path)
 		if e != nil && !os.IsNotExist(e) {
 			return e
 		}
 		if e == nil && info.Size() != 0 {
 			return nil
 		}
-		bys, e = cache.GetBytes(source)
 	}
+	bys, e := get()
 	if e != nil {
 		return e
 	}
@@ -211,7 +349,7 @@ func imageCache(cache *Cache, m Content, sample bool) (e error) {
 
 	for p := range path {
 		if p != "" {
-			_, err := cache.Get(p)
+			_, err := cache.GetImage(p, false)
 			if err != nil && !os.IsExist(err) {
 				log.Error(err)
 			}
@@ -225,6 +363,14 @@ func TrimEnd(source string) string {
 	return strings.Split(source, "?")[0]
 }
 
+// outputExt the extension of the output image
+func outputExt(option *OutputInfo, source string) string {
+	if option.Image != nil {
+		return option.Image.Ext()
+	}
+	return Ext(source)
+}
+
 // Ext ...
 func Ext(source string) string {
 	ext := filepath.Ext(TrimEnd(source))
@@ -0,0 +1,89 @@
+package scrape
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestCopyInfoUnchanged ...
+func TestCopyInfoUnchanged(t *testing.T) {
+	dir, e := ioutil.TempDir("", "output")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, DefaultInfoName)
+	//an info file written before the checksums
+	if e = writeInfo(&Content{ID: "ABC-001", Title: "title", Poster: "poster"}, path); e != nil {
+		t.Fatal(e)
+	}
+	old := time.Now().Add(-time.Hour)
+	if e = os.Chtimes(path, old, old); e != nil {
+		t.Fatal(e)
+	}
+	for _, policy := range []MergePolicy{PolicyFillEmpty, PolicyKeepManual} {
+		if e = copyInfo(&Content{ID: "ABC-001", Title: "title"}, dir, DefaultInfoName, policy); e != nil {
+			t.Fatal(e)
+		}
+		info, e := os.Stat(path)
+		if e != nil || !info.ModTime().Equal(old) {
+			t.Fatalf("%s: the unchanged info is written again", policy)
+		}
+	}
+	if e = copyInfo(&Content{ID: "ABC-001", Title: "title", Plot: "plot"}, dir, DefaultInfoName, PolicyFillEmpty); e != nil {
+		t.Fatal(e)
+	}
+	content, e := readInfo(path)
+	if e != nil || content.Plot != "plot" || content.Poster != "poster" || content.Checksums == nil {
+		t.Fatal(content, e)
+	}
+}
+
+const testLockedNfo = `<?xml version="1.0" encoding="utf-8" standalone="yes"?>
+<movie>
+  <title>Fixed Title</title>
+  <plot>old plot</plot>
+  <lockdata>false</lockdata>
+  <lockedfields>Name|Cast</lockedfields>
+  <genre>old genre</genre>
+  <actor><name>Fixed Actor</name><type>Actor</type></actor>
+</movie>`
+
+// TestCopyInfoNfoLocked ...
+func TestCopyInfoNfoLocked(t *testing.T) {
+	dir, e := ioutil.TempDir("", "output")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	if e = ioutil.WriteFile(filepath.Join(dir, "abc-001.nfo"), []byte(testLockedNfo), 0644); e != nil {
+		t.Fatal(e)
+	}
+	e = copyInfo(&Content{
+		ID:     "ABC-001",
+		Title:  "new title",
+		Plot:   "new plot",
+		Genres: []*Genre{{Content: "new genre"}},
+		Actors: []*Star{{Name: "new actor"}},
+	}, dir, "abc-001.nfo", PolicyOverwrite)
+	if e != nil {
+		t.Fatal(e)
+	}
+	bys, e := ioutil.ReadFile(filepath.Join(dir, "abc-001.nfo"))
+	if e != nil {
+		t.Fatal(e)
+	}
+	if string(bys) != testLockedNfo {
+		t.Fatalf("the nfo of the media server is changed: %s", bys)
+	}
+	content, e := readNfo(filepath.Join(dir, "abc-001.nfo"))
+	if e != nil {
+		t.Fatal(e)
+	}
+	if len(content.Locked) != 2 || !content.IsLocked("Title") || !content.IsLocked("Actors") || content.IsLocked("Genres") {
+		t.Fatal(content.Locked)
+	}
+}
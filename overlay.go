@@ -0,0 +1,248 @@
+package scrape
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// BadgeCondition check if the badge is drawn for the content and its video file
+type BadgeCondition func(content *Content, file string) bool
+
+// BadgePosition ...
+type BadgePosition int
+
+// BadgePosition detail ...
+const (
+	BadgeTopLeft BadgePosition = iota
+	BadgeTopRight
+	BadgeBottomLeft
+	BadgeBottomRight
+)
+
+// Badge ...
+type Badge struct {
+	Name       string
+	Text       string      //rendered with the built in font when Image is nil
+	Image      image.Image //custom badge asset
+	Background color.Color
+	Foreground color.Color
+	Condition  BadgeCondition
+}
+
+// OverlayOption draw the badges on the poster
+type OverlayOption struct {
+	Badges   []*Badge
+	Position BadgePosition
+	Size     float64 //badge height relative to the poster height
+	Margin   float64 //margin relative to the poster height
+}
+
+// BadgeUncensored ...
+func BadgeUncensored() *Badge {
+	return &Badge{
+		Name:       "uncensored",
+		Text:       "UNCENSORED",
+		Background: color.RGBA{R: 200, G: 30, B: 30, A: 230},
+		Foreground: color.White,
+		Condition: func(content *Content, file string) bool {
+			return content.Uncensored
+		},
+	}
+}
+
+// BadgeSubtitle the file name ends with -C
+func BadgeSubtitle() *Badge {
+	return &Badge{
+		Name:       "sub",
+		Text:       "SUB",
+		Background: color.RGBA{R: 30, G: 120, B: 200, A: 230},
+		Foreground: color.White,
+		Condition: func(content *Content, file string) bool {
+			name := strings.ToUpper(strings.TrimSuffix(filepath.Base(file), filepath.Ext(file)))
+			return strings.HasSuffix(name, "-C") || strings.HasSuffix(name, "_C") || strings.HasSuffix(name, "-UC")
+		},
+	}
+}
+
+// badge4KRegexp 4K,2160P or UHD as a separated token of the file name,like ABP-891.4K.mp4 or ABP-891 [2160p].mkv
+var badge4KRegexp = regexp.MustCompile(`(?i)(^|[^a-z0-9])(4k|2160p|uhd)([^a-z0-9]|$)`)
+
+// Badge4K the file name has a 4K,2160P or UHD token
+func Badge4K() *Badge {
+	return &Badge{
+		Name:       "4k",
+		Text:       "4K",
+		Background: color.RGBA{R: 230, G: 160, B: 20, A: 230},
+		Foreground: color.Black,
+		Condition: func(content *Content, file string) bool {
+			name := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+			return badge4KRegexp.MatchString(name)
+		},
+	}
+}
+
+// DefaultOverlayOption ...
+func DefaultOverlayOption() *OverlayOption {
+	return &OverlayOption{
+		Badges:   []*Badge{BadgeUncensored(), BadgeSubtitle(), Badge4K()},
+		Position: BadgeTopLeft,
+		Size:     0.06,
+		Margin:   0.02,
+	}
+}
+
+// active return the badges matched the content
+func (o *OverlayOption) active(content *Content, file string) []*Badge {
+	var badges []*Badge
+	for _, b := range o.Badges {
+		if b.Condition == nil || b.Condition(content, file) {
+			badges = append(badges, b)
+		}
+	}
+	return badges
+}
+
+// overlayBadges draw the badges stacked from the corner of the position
+func overlayBadges(img image.Image, option *OverlayOption, badges []*Badge) image.Image {
+	if len(badges) == 0 {
+		return img
+	}
+	b := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, b.Min, draw.Src)
+	height := int(float64(b.Dy()) * option.Size)
+	if height < 8 {
+		height = 8
+	}
+	margin := int(float64(b.Dy()) * option.Margin)
+	y := margin
+	if option.Position == BadgeBottomLeft || option.Position == BadgeBottomRight {
+		y = b.Dy() - margin - height
+	}
+	for _, badge := range badges {
+		bi := badge.render(height)
+		x := margin
+		if option.Position == BadgeTopRight || option.Position == BadgeBottomRight {
+			x = b.Dx() - margin - bi.Bounds().Dx()
+		}
+		r := image.Rect(x, y, x+bi.Bounds().Dx(), y+bi.Bounds().Dy())
+		draw.Draw(dst, r, bi, bi.Bounds().Min, draw.Over)
+		if option.Position == BadgeBottomLeft || option.Position == BadgeBottomRight {
+			y -= height + margin/2
+		} else {
+			y += height + margin/2
+		}
+	}
+	return dst
+}
+
+// key identify how the badge looks in the image cache
+func (badge *Badge) key() string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s|%s|%v|%v", badge.Name, badge.Text, badge.Background, badge.Foreground)
+	if badge.Image != nil {
+		b := badge.Image.Bounds()
+		fmt.Fprintf(h, "|%v", b)
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				r, g, bl, a := badge.Image.At(x, y).RGBA()
+				_, _ = h.Write([]byte{byte(r >> 8), byte(g >> 8), byte(bl >> 8), byte(a >> 8)})
+			}
+		}
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))[:16]
+}
+
+// render the badge to the height
+func (badge *Badge) render(height int) image.Image {
+	if badge.Image != nil {
+		w := badge.Image.Bounds().Dx() * height / badge.Image.Bounds().Dy()
+		return resizeImage(badge.Image, w)
+	}
+	//glyphs are 5x7 with one pixel spacing and a 2 pixels border
+	scale := height / (badgeGlyphHeight + 4)
+	if scale < 1 {
+		scale = 1
+	}
+	text := strings.ToUpper(badge.Text)
+	width := (len(text)*(badgeGlyphWidth+1) - 1 + 4) * scale
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	bg := badge.Background
+	if bg == nil {
+		bg = color.Black
+	}
+	fg := badge.Foreground
+	if fg == nil {
+		fg = color.White
+	}
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: bg}, image.Point{}, draw.Src)
+	top := (height - badgeGlyphHeight*scale) / 2
+	for i, r := range text {
+		glyph, b := badgeFont[r]
+		if !b {
+			continue
+		}
+		left := (2 + i*(badgeGlyphWidth+1)) * scale
+		for row, line := range glyph {
+			for col, c := range line {
+				if c != '#' {
+					continue
+				}
+				rect := image.Rect(left+col*scale, top+row*scale, left+(col+1)*scale, top+(row+1)*scale)
+				draw.Draw(img, rect, &image.Uniform{C: fg}, image.Point{}, draw.Src)
+			}
+		}
+	}
+	return img
+}
+
+const badgeGlyphWidth = 5
+const badgeGlyphHeight = 7
+
+// badgeFont a 5x7 bitmap font of the badge texts
+var badgeFont = map[rune][badgeGlyphHeight]string{
+	'A': {".###.", "#...#", "#...#", "#####", "#...#", "#...#", "#...#"},
+	'B': {"####.", "#...#", "#...#", "####.", "#...#", "#...#", "####."},
+	'C': {".###.", "#...#", "#....", "#....", "#....", "#...#", ".###."},
+	'D': {"####.", "#...#", "#...#", "#...#", "#...#", "#...#", "####."},
+	'E': {"#####", "#....", "#....", "####.", "#....", "#....", "#####"},
+	'F': {"#####", "#....", "#....", "####.", "#....", "#....", "#...."},
+	'G': {".###.", "#...#", "#....", "#.###", "#...#", "#...#", ".####"},
+	'H': {"#...#", "#...#", "#...#", "#####", "#...#", "#...#", "#...#"},
+	'I': {".###.", "..#..", "..#..", "..#..", "..#..", "..#..", ".###."},
+	'J': {"..###", "...#.", "...#.", "...#.", "...#.", "#..#.", ".##.."},
+	'K': {"#...#", "#..#.", "#.#..", "##...", "#.#..", "#..#.", "#...#"},
+	'L': {"#....", "#....", "#....", "#....", "#....", "#....", "#####"},
+	'M': {"#...#", "##.##", "#.#.#", "#.#.#", "#...#", "#...#", "#...#"},
+	'N': {"#...#", "#...#", "##..#", "#.#.#", "#..##", "#...#", "#...#"},
+	'O': {".###.", "#...#", "#...#", "#...#", "#...#", "#...#", ".###."},
+	'P': {"####.", "#...#", "#...#", "####.", "#....", "#....", "#...."},
+	'Q': {".###.", "#...#", "#...#", "#...#", "#.#.#", "#..#.", ".##.#"},
+	'R': {"####.", "#...#", "#...#", "####.", "#.#..", "#..#.", "#...#"},
+	'S': {".####", "#....", "#....", ".###.", "....#", "....#", "####."},
+	'T': {"#####", "..#..", "..#..", "..#..", "..#..", "..#..", "..#.."},
+	'U': {"#...#", "#...#", "#...#", "#...#", "#...#", "#...#", ".###."},
+	'V': {"#...#", "#...#", "#...#", "#...#", "#...#", ".#.#.", "..#.."},
+	'W': {"#...#", "#...#", "#...#", "#.#.#", "#.#.#", "#.#.#", ".#.#."},
+	'X': {"#...#", "#...#", ".#.#.", "..#..", ".#.#.", "#...#", "#...#"},
+	'Y': {"#...#", "#...#", ".#.#.", "..#..", "..#..", "..#..", "..#.."},
+	'Z': {"#####", "....#", "...#.", "..#..", ".#...", "#....", "#####"},
+	'0': {".###.", "#...#", "#..##", "#.#.#", "##..#", "#...#", ".###."},
+	'1': {"..#..", ".##..", "..#..", "..#..", "..#..", "..#..", ".###."},
+	'2': {".###.", "#...#", "....#", "...#.", "..#..", ".#...", "#####"},
+	'3': {"#####", "...#.", "..#..", "...#.", "....#", "#...#", ".###."},
+	'4': {"...#.", "..##.", ".#.#.", "#..#.", "#####", "...#.", "...#."},
+	'5': {"#####", "#....", "####.", "....#", "....#", "#...#", ".###."},
+	'6': {"..##.", ".#...", "#....", "####.", "#...#", "#...#", ".###."},
+	'7': {"#####", "....#", "...#.", "..#..", ".#...", ".#...", ".#..."},
+	'8': {".###.", "#...#", "#...#", ".###.", "#...#", "#...#", ".###."},
+	'9': {".###.", "#...#", "#...#", ".####", "....#", "...#.", ".##.."},
+	'-': {".....", ".....", ".....", "#####", ".....", ".....", "....."},
+	'+': {".....", "..#..", "..#..", "#####", "..#..", "..#..", "....."},
+}
@@ -0,0 +1,105 @@
+package scrape
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestOverlayBadges ...
+func TestOverlayBadges(t *testing.T) {
+	for r, glyph := range badgeFont {
+		for _, line := range glyph {
+			if len(line) != badgeGlyphWidth {
+				t.Fatalf("glyph %c: %q", r, line)
+			}
+		}
+	}
+
+	option := DefaultOverlayOption()
+	badges := option.active(&Content{Uncensored: true}, "/video/ABP-891-C.mp4")
+	if len(badges) != 2 || badges[0].Name != "uncensored" || badges[1].Name != "sub" {
+		t.Fatal(badges)
+	}
+	for file, want := range map[string]bool{
+		"abp-891.2160p.mkv":      true,
+		"/video/ABP-891-4K.mp4":  true,
+		"ABP-891 [UHD].mkv":      true,
+		"4k/ABP-891.mp4":         false,
+		"ABP-894K.mp4":           false,
+		"4kids-001.mp4":          false,
+		"/video/MK4KA-001.mp4":   false,
+		"abp-891.2160p60fps.mkv": false,
+	} {
+		if b := Badge4K().Condition(&Content{}, file); b != want {
+			t.Errorf("4k badge of %s: got %v want %v", file, b, want)
+		}
+	}
+
+	poster := image.NewRGBA(image.Rect(0, 0, 381, 538))
+	img := overlayBadges(poster, option, badges)
+	margin := int(538 * option.Margin)
+	//the uncensored badge is red
+	if r, g, _, _ := img.At(margin+1, margin+1).RGBA(); r>>8 < 100 || g >= r {
+		t.Fatal("badge is not drawn")
+	}
+	if _, _, _, a := img.At(380, 537).RGBA(); a != 0 {
+		t.Fatal("poster is changed outside the badge")
+	}
+}
+
+// TestOverlayCacheSource ...
+func TestOverlayCacheSource(t *testing.T) {
+	cache := testCache(t)
+	fill := color.RGBA{R: 255, A: 255}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		img := image.NewRGBA(image.Rect(0, 0, 400, 538))
+		draw.Draw(img, img.Bounds(), &image.Uniform{C: fill}, image.Point{}, draw.Src)
+		w.Header().Set("Content-Type", "image/png")
+		_ = png.Encode(w, img)
+	}))
+	defer server.Close()
+
+	option := DefaultImageOption()
+	option.Format = "png"
+	option.Overlay = DefaultOverlayOption()
+	process := imageProcess{name: "poster", badges: []*Badge{BadgeSubtitle()}}
+	red, e := processImage(cache, server.URL, option, process)
+	if e != nil {
+		t.Fatal(e)
+	}
+	//the source changed on the site and is got again
+	fill = color.RGBA{G: 255, A: 255}
+	if _, e = cache.GetImage(server.URL, true); e != nil {
+		t.Fatal(e)
+	}
+	green, e := processImage(cache, server.URL, option, process)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if bytes.Equal(red, green) {
+		t.Fatal("the poster of the old source is cached")
+	}
+	img, e := png.Decode(bytes.NewReader(green))
+	if e != nil {
+		t.Fatal(e)
+	}
+	if _, g, _, _ := img.At(399, 537).RGBA(); g>>8 != 255 {
+		t.Fatal("the poster is not processed from the new source")
+	}
+
+	//another badge with the same name looks different
+	process.badges = []*Badge{{Name: "sub", Text: "CC"}}
+	other, e := processImage(cache, server.URL, option, process)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if bytes.Equal(other, green) {
+		t.Fatal("the badges with the same name share the cache")
+	}
+}
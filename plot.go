@@ -0,0 +1,58 @@
+package scrape
+
+import (
+	"github.com/goextension/log"
+)
+
+// IPlot ...
+type IPlot interface {
+	Name() string
+	Language() GrabLanguage
+	SetForce(force bool)
+	Plot(id string) (string, error)
+}
+
+// PlotOption add a plot source,sources are tried in order until one returns a plot
+func PlotOption(plot IPlot) Options {
+	return func(impl *scrapeImpl) {
+		impl.plots = append(impl.plots, plot)
+	}
+}
+
+func plotEnrich(plots []IPlot, content *Content, force bool) {
+	if content.Plot != "" {
+		if content.PlotFrom == "" {
+			content.PlotFrom = content.From
+		}
+		return
+	}
+	for _, p := range plots {
+		p.SetForce(force)
+		plot, e := p.Plot(content.ID)
+		if e != nil {
+			log.Errorw("plot", "name", p.Name(), "id", content.ID, "error", e)
+			continue
+		}
+		if plot == "" {
+			continue
+		}
+		content.Plot = plot
+		content.PlotFrom = p.Name()
+		lang := p.Language().String()
+		if content.Localized == nil {
+			content.Localized = make(map[string]*Localized)
+		}
+		if l, b := content.Localized[lang]; b {
+			l.Plot = plot
+		} else {
+			content.Localized[lang] = &Localized{
+				Language: lang,
+				Plot:     plot,
+			}
+		}
+		if debug {
+			log.Infow("plot", "name", p.Name(), "id", content.ID, "plot", plot)
+		}
+		return
+	}
+}
@@ -0,0 +1,83 @@
+package scrape
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// DefaultDmmMainPage ...
+const DefaultDmmMainPage = "https://www.dmm.co.jp"
+const dmmDigitalDetail = "/digital/videoa/-/detail/=/cid=%s/"
+const dmmMonoDetail = "/mono/dvd/-/detail/=/cid=%s/"
+
+var dmmIDRegexp = regexp.MustCompile(`^([a-zA-Z]+)-?(\d+)$`)
+
+func init() {
+	siteCookies["www.dmm.co.jp"] = []*http.Cookie{
+		{Name: "age_check_done", Value: "1"},
+	}
+}
+
+// dmmContentID convert an id like ABP-891 to the dmm content id abp00891
+func dmmContentID(id string) string {
+	match := dmmIDRegexp.FindStringSubmatch(strings.TrimSpace(id))
+	if match == nil {
+		return strings.ToLower(id)
+	}
+	number := strings.TrimLeft(match[2], "0")
+	for len(number) < 5 {
+		number = "0" + number
+	}
+	return strings.ToLower(match[1]) + number
+}
+
+// plotDmm get the plot with the dmm grab,the ids and the detail pages are handled by the grab
+type plotDmm struct {
+	grab  *grabDmm
+	force bool
+}
+
+// Name ...
+func (p *plotDmm) Name() string {
+	return p.grab.Name()
+}
+
+// Language ...
+func (p *plotDmm) Language() GrabLanguage {
+	return LanguageJapanese
+}
+
+// SetForce ...
+func (p *plotDmm) SetForce(force bool) {
+	p.force = force
+}
+
+// Plot ...
+func (p *plotDmm) Plot(id string) (string, error) {
+	grab := p.grab.clone()
+	grab.sample = false
+	grab.force = p.force
+	find, e := grab.Find(id)
+	if e != nil {
+		return "", e
+	}
+	contents, e := find.Result()
+	if e != nil {
+		return "", e
+	}
+	for _, c := range contents {
+		if c.Plot != "" {
+			return c.Plot, nil
+		}
+	}
+	return "", errors.New("no plot found")
+}
+
+// NewPlotDmm ...
+func NewPlotDmm(ops ...GrabDmmOptions) IPlot {
+	return &plotDmm{
+		grab: NewGrabDmm(ops...).(*grabDmm),
+	}
+}
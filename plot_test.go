@@ -0,0 +1,47 @@
+package scrape
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestDmmContentID ...
+func TestDmmContentID(t *testing.T) {
+	for id, cid := range map[string]string{
+		"ABP-891":   "abp00891",
+		"abp891":    "abp00891",
+		"SSNI-0123": "ssni00123",
+		"FC2-PPV-1": "fc2-ppv-1",
+	} {
+		if v := dmmContentID(id); v != cid {
+			t.Errorf("id %s: got %s want %s", id, v, cid)
+		}
+	}
+}
+
+// TestPlotDmm ...
+func TestPlotDmm(t *testing.T) {
+	testCache(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == fmt.Sprintf(dmmMonoDetail, "abp891") {
+			fmt.Fprint(w, `<html><head><meta property="og:description" content="The mono plot."></head><body>
+<h1 id="title">The Title</h1><table class="mg-b20"><tr><td>品番：</td><td>abp891</td></tr></table></body></html>`)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	plot := NewPlotDmm()
+	plot.(*plotDmm).grab.MainPage(server.URL)
+	plot.SetForce(true)
+	v, e := plot.Plot("ABP-891")
+	if e != nil || v != "The mono plot." {
+		t.Fatal(v, e)
+	}
+	if _, e := plot.Plot("ABP-892"); e == nil {
+		t.Fatal("the plot of ABP-892 is not found")
+	}
+}
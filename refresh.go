@@ -0,0 +1,274 @@
+package scrape
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goextension/log"
+)
+
+// RefreshField a key field,a record missing one of them is refreshed
+type RefreshField string
+
+// RefreshField detail ...
+const (
+	RefreshPoster RefreshField = "poster"
+	RefreshActors RefreshField = "actors"
+	RefreshPlot   RefreshField = "plot"
+	RefreshGenres RefreshField = "genres"
+	RefreshSample RefreshField = "sample"
+)
+
+// RefreshResult ...
+type RefreshResult struct {
+	Path   string
+	ID     string
+	Reason string
+	Diff   *ContentDiff
+	Error  error
+}
+
+// Refresher rescrape the stale or incomplete info files under the root path
+type Refresher struct {
+	lock     sync.Mutex
+	scrape   IScrape
+	root     string
+	exts     []string
+	age      time.Duration
+	required []RefreshField
+	policy   MergePolicy
+	report   func(result *RefreshResult)
+	stop     chan struct{}
+}
+
+// RefreshOptions ...
+type RefreshOptions func(r *Refresher)
+
+// RefreshRoot ...
+func RefreshRoot(root string) RefreshOptions {
+	return func(r *Refresher) {
+		r.root = root
+	}
+}
+
+// RefreshExts the extensions of the info files,only the json info written by the output is parsed
+func RefreshExts(exts ...string) RefreshOptions {
+	return func(r *Refresher) {
+		r.exts = exts
+	}
+}
+
+// RefreshAge the records scraped before the age are refreshed
+func RefreshAge(age time.Duration) RefreshOptions {
+	return func(r *Refresher) {
+		r.age = age
+	}
+}
+
+// RefreshRequired ...
+func RefreshRequired(fields ...RefreshField) RefreshOptions {
+	return func(r *Refresher) {
+		r.required = fields
+	}
+}
+
+// RefreshPolicy how the rescraped content is applied to the info file
+func RefreshPolicy(policy MergePolicy) RefreshOptions {
+	return func(r *Refresher) {
+		r.policy = policy
+	}
+}
+
+// RefreshReport called after every refreshed record
+func RefreshReport(f func(result *RefreshResult)) RefreshOptions {
+	return func(r *Refresher) {
+		r.report = f
+	}
+}
+
+// NewRefresher the scrape is cleared and forced while refreshing,so it should not be shared
+func NewRefresher(scrape IScrape, opts ...RefreshOptions) *Refresher {
+	r := &Refresher{
+		scrape:   scrape,
+		root:     DefaultOutputPath,
+		exts:     []string{DefaultInfoName},
+		age:      30 * 24 * time.Hour,
+		required: []RefreshField{RefreshPoster, RefreshActors, RefreshPlot},
+		policy:   PolicyKeepManual,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Start refresh every interval until Stop
+func (r *Refresher) Start(interval time.Duration) error {
+	r.lock.Lock()
+	if r.stop != nil {
+		r.lock.Unlock()
+		return errors.New("refresher is started")
+	}
+	stop := make(chan struct{})
+	r.stop = stop
+	r.lock.Unlock()
+
+	tick := time.NewTicker(interval)
+	defer tick.Stop()
+	for {
+		_, e := r.Refresh()
+		if e != nil {
+			log.Errorw("refresh", "root", r.root, "error", e)
+		}
+		select {
+		case <-stop:
+			return nil
+		case <-tick.C:
+		}
+	}
+}
+
+// Stop ...
+func (r *Refresher) Stop() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.stop != nil {
+		close(r.stop)
+		r.stop = nil
+	}
+}
+
+// Refresh walk the root once and rescrape the matched records
+func (r *Refresher) Refresh() ([]*RefreshResult, error) {
+	var results []*RefreshResult
+	e := filepath.Walk(r.root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !r.match(path) {
+			return nil
+		}
+		content, e := readInfo(path)
+		if e != nil || content.ID == "" {
+			log.Warnw("refresh", "path", path, "error", e)
+			return nil
+		}
+		//the info files written before the scrape time was kept use the file time
+		scraped := content.Scraped
+		if scraped.IsZero() {
+			scraped = info.ModTime()
+		}
+		reason := r.reason(content, scraped)
+		if reason == "" {
+			return nil
+		}
+		result := r.refresh(path, content)
+		result.Reason = reason
+		if r.report != nil {
+			r.report(result)
+		}
+		results = append(results, result)
+		return nil
+	})
+	return results, e
+}
+
+func (r *Refresher) match(path string) bool {
+	name := filepath.Base(path)
+	for _, ext := range r.exts {
+		if name == ext || strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// missingField check the field is empty in the content
+func missingField(content *Content, field RefreshField) bool {
+	switch field {
+	case RefreshPoster:
+		return content.Poster == ""
+	case RefreshActors:
+		return len(content.Actors) == 0
+	case RefreshPlot:
+		return content.Plot == ""
+	case RefreshGenres:
+		return len(content.Genres) == 0
+	case RefreshSample:
+		return len(content.Sample) == 0
+	}
+	return false
+}
+
+// reason return why the record needs refreshing,empty means it is fine
+func (r *Refresher) reason(content *Content, scraped time.Time) string {
+	if r.age > 0 && time.Since(scraped) > r.age {
+		return "stale"
+	}
+	for _, field := range r.required {
+		if missingField(content, field) {
+			return "missing " + string(field)
+		}
+	}
+	return ""
+}
+
+func (r *Refresher) refresh(path string, old *Content) *RefreshResult {
+	result := &RefreshResult{
+		Path: path,
+		ID:   old.ID,
+	}
+	r.scrape.Clear()
+	r.scrape.Force(true)
+	defer r.scrape.Force(false)
+	result.Error = r.scrape.Find(old.ID)
+	if result.Error != nil {
+		return result
+	}
+	var contents []*Content
+	_ = r.scrape.Range(func(key string, content Content) error {
+		c := content
+		contents = append(contents, &c)
+		return nil
+	})
+	content := MergeOptimize(old.ID, contents)
+	if content == nil {
+		result.Error = ErrNotFound
+		return result
+	}
+	content.Scraped = time.Now()
+	merged := ApplyPolicy(r.policy, old, content)
+	result.Diff = Diff(old, merged)
+	if debug {
+		log.Infow("refresh", "path", path, "id", old.ID, "diff", result.Diff.String())
+	}
+	result.Error = writeInfo(merged, path)
+	return result
+}
+
+func readInfo(path string) (*Content, error) {
+	bys, e := ioutil.ReadFile(path)
+	if e != nil {
+		return nil, e
+	}
+	content := new(Content)
+	e = json.Unmarshal(bys, content)
+	if e != nil {
+		return nil, e
+	}
+	return content, nil
+}
+
+func writeInfo(content *Content, path string) error {
+	bys, e := json.MarshalIndent(content, "", " ")
+	if e != nil {
+		return e
+	}
+	return ioutil.WriteFile(path, bys, 0644)
+}
@@ -0,0 +1,64 @@
+package scrape
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestRefresh ...
+func TestRefresh(t *testing.T) {
+	dir, e := ioutil.TempDir("", "refresh")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "ABC-001", ".info")
+	_ = os.MkdirAll(filepath.Dir(path), os.ModePerm)
+	if e = writeInfo(&Content{ID: "ABC-001", Title: "old", Poster: "poster", Plot: "plot"}, path); e != nil {
+		t.Fatal(e)
+	}
+
+	impl := &scrapeImpl{
+		contents: make(map[string][]Content),
+		grabs:    []IGrab{&searchGrabTest{name: "test", pages: 1}},
+	}
+	results, e := NewRefresher(impl, RefreshRoot(dir)).Refresh()
+	if e != nil {
+		t.Fatal(e)
+	}
+	if len(results) != 1 || results[0].Reason != "missing actors" || results[0].Error != nil {
+		t.Fatalf("%+v", results)
+	}
+	//the rescraped content has no title,poster and plot,the old values are kept
+	if !results[0].Diff.Empty() {
+		t.Fatalf("%+v", results[0].Diff)
+	}
+	content, e := readInfo(path)
+	if e != nil || content.Title != "old" || content.Poster != "poster" || content.Plot != "plot" {
+		t.Fatal(content, e)
+	}
+	if content.Scraped.IsZero() {
+		t.Fatal("no scrape time")
+	}
+	info, e := os.Stat(path)
+	if e != nil || info.Mode().Perm() != 0644 {
+		t.Fatal(info, e)
+	}
+
+	//the scrape time in the info decides the age,not the time of the file
+	content.Scraped = time.Now().Add(-48 * time.Hour)
+	if e = writeInfo(content, path); e != nil {
+		t.Fatal(e)
+	}
+	results, e = NewRefresher(impl, RefreshRoot(dir), RefreshAge(24*time.Hour), RefreshRequired()).Refresh()
+	if e != nil || len(results) != 1 || results[0].Reason != "stale" {
+		t.Fatalf("%+v %v", results, e)
+	}
+	results, e = NewRefresher(impl, RefreshRoot(dir), RefreshAge(24*time.Hour), RefreshRequired()).Refresh()
+	if e != nil || len(results) != 0 {
+		t.Fatalf("%+v %v", results, e)
+	}
+}
@@ -0,0 +1,274 @@
+package scrape
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/goextension/log"
+)
+
+// GrabCapability what a grab is able to find
+type GrabCapability struct {
+	Censored   bool           `yaml:"censored" json:"censored"`
+	Uncensored bool           `yaml:"uncensored" json:"uncensored"`
+	Amateur    bool           `yaml:"amateur" json:"amateur"`
+	Languages  []GrabLanguage `yaml:"-" json:"-"`
+	Sample     bool           `yaml:"sample" json:"sample"`
+	ActorPage  bool           `yaml:"actor_page" json:"actor_page"`
+	Plot       bool           `yaml:"plot" json:"plot"`
+	Patterns   []string       `yaml:"patterns" json:"patterns"` //regexps of the supported ids,empty supports every id
+	Priority   int            `yaml:"priority" json:"priority"` //the grabs of a higher priority are tried first
+}
+
+// GrabRegistration a registered grab
+type GrabRegistration struct {
+	Name       string
+	Capability GrabCapability
+	New        func() IGrab
+	patterns   []*regexp.Regexp
+}
+
+var grabRegistry = struct {
+	lock  sync.RWMutex
+	grabs map[string]*GrabRegistration
+	order []string
+}{
+	grabs: make(map[string]*GrabRegistration),
+}
+
+func init() {
+	RegisterGrab("javbus", GrabCapability{
+		Censored:   true,
+		Uncensored: true,
+		Languages:  []GrabLanguage{LanguageEnglish, LanguageJapanese, LanguageChineseSimple, LanguageChineseTraditional, LanguageKorea},
+		Sample:     true,
+		ActorPage:  true,
+	}, func() IGrab { return NewGrabJavbus() })
+	RegisterGrab("javdb", GrabCapability{
+		Censored:   true,
+		Uncensored: true,
+		Amateur:    true,
+		Languages:  []GrabLanguage{LanguageChineseTraditional},
+		Sample:     true,
+		ActorPage:  true,
+	}, func() IGrab { return NewGrabJavdb() })
+	RegisterGrab("dmm", GrabCapability{
+		Censored:  true,
+		Languages: []GrabLanguage{LanguageJapanese},
+		Sample:    true,
+		Plot:      true,
+	}, func() IGrab { return NewGrabDmm() })
+	RegisterGrab("fc2", GrabCapability{
+		Amateur:   true,
+		Languages: []GrabLanguage{LanguageJapanese},
+		Sample:    true,
+		Plot:      true,
+		Patterns:  []string{`^FC2`},
+	}, func() IGrab { return NewGrabFc2() })
+	RegisterGrab("javlibrary", GrabCapability{
+		Censored:  true,
+		Languages: []GrabLanguage{LanguageEnglish, LanguageJapanese, LanguageChineseSimple, LanguageChineseTraditional},
+		ActorPage: true,
+	}, func() IGrab { return NewGrabJavlibrary() })
+	RegisterGrab("mgstage", GrabCapability{
+		Amateur:   true,
+		Languages: []GrabLanguage{LanguageJapanese},
+		Sample:    true,
+		Plot:      true,
+		Patterns:  []string{`^\d{3,4}[a-z]{2,8}[-_ ]?\d{2,5}$`, `^(` + strings.Join(AmateurPrefixes, "|") + `)[-_ ]?\d{2,5}$`},
+	}, func() IGrab { return NewGrabMgstage() })
+	for _, site := range []*uncensoredSite{uncensoredSiteCarib, uncensoredSite1pondo, uncensoredSite10musume, uncensoredSiteHeyzo} {
+		site := site
+		RegisterGrab(site.name, GrabCapability{
+			Uncensored: true,
+			Languages:  []GrabLanguage{LanguageJapanese},
+			Sample:     true,
+			Plot:       true,
+			Patterns:   site.patterns,
+		}, func() IGrab { return newGrabUncensored(site) })
+	}
+}
+
+// RegisterGrab register a grab by name,a registered name is replaced
+func RegisterGrab(name string, capability GrabCapability, f func() IGrab) error {
+	r := &GrabRegistration{
+		Name:       name,
+		Capability: capability,
+		New:        f,
+	}
+	for _, pattern := range capability.Patterns {
+		compile, e := regexp.Compile("(?i)" + pattern)
+		if e != nil {
+			return fmt.Errorf("register %s pattern %s: %w", name, pattern, e)
+		}
+		r.patterns = append(r.patterns, compile)
+	}
+	grabRegistry.lock.Lock()
+	defer grabRegistry.lock.Unlock()
+	if _, b := grabRegistry.grabs[name]; !b {
+		grabRegistry.order = append(grabRegistry.order, name)
+	}
+	grabRegistry.grabs[name] = r
+	return nil
+}
+
+// RegisterGrabDefinition register a grab loaded from a definition
+func RegisterGrabDefinition(def *GrabDefinition) error {
+	capability := def.Capability
+	for name := range def.Languages {
+		for language, v := range languageGrabStringList {
+			if v == name {
+				capability.Languages = append(capability.Languages, language)
+			}
+		}
+	}
+	sort.Slice(capability.Languages, func(i, j int) bool {
+		return capability.Languages[i] < capability.Languages[j]
+	})
+	return RegisterGrab(def.Name, capability, func() IGrab { return NewGrabFromDefinition(def) })
+}
+
+// UnregisterGrab ...
+func UnregisterGrab(name string) {
+	grabRegistry.lock.Lock()
+	defer grabRegistry.lock.Unlock()
+	if _, b := grabRegistry.grabs[name]; !b {
+		return
+	}
+	delete(grabRegistry.grabs, name)
+	for i, v := range grabRegistry.order {
+		if v == name {
+			grabRegistry.order = append(grabRegistry.order[:i], grabRegistry.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// RegisteredGrabs return the registered grabs in register order
+func RegisteredGrabs() []*GrabRegistration {
+	grabRegistry.lock.RLock()
+	defer grabRegistry.lock.RUnlock()
+	var list []*GrabRegistration
+	for _, name := range grabRegistry.order {
+		list = append(list, grabRegistry.grabs[name])
+	}
+	return list
+}
+
+// LookupGrab ...
+func LookupGrab(name string) (*GrabRegistration, bool) {
+	grabRegistry.lock.RLock()
+	defer grabRegistry.lock.RUnlock()
+	r, b := grabRegistry.grabs[name]
+	return r, b
+}
+
+// NewRegisteredGrab create a registered grab by name
+func NewRegisteredGrab(name string) (IGrab, error) {
+	r, b := LookupGrab(name)
+	if !b {
+		return nil, fmt.Errorf("grab %s is not registered", name)
+	}
+	return r.New(), nil
+}
+
+// Match check the id with the patterns of the grab
+func (r *GrabRegistration) Match(id string) bool {
+	if len(r.patterns) == 0 {
+		return true
+	}
+	for _, p := range r.patterns {
+		if p.MatchString(id) {
+			return true
+		}
+	}
+	return false
+}
+
+// SupportKind check the grab finds the kind of ids,every grab supports the unknown ids
+func (c GrabCapability) SupportKind(kind IDKind) bool {
+	switch kind {
+	case IDCensored:
+		return c.Censored
+	case IDHeyzo, IDDate:
+		return c.Uncensored
+	case IDFC2, IDAmateur:
+		return c.Amateur
+	}
+	return true
+}
+
+// Support check the grab supports the id by the kind and the patterns
+func (r *GrabRegistration) Support(id string) bool {
+	return r.Capability.SupportKind(ClassifyID(id)) && r.Match(id)
+}
+
+// SupportLanguage ...
+func (c GrabCapability) SupportLanguage(language GrabLanguage) bool {
+	for _, l := range c.Languages {
+		if l == language {
+			return true
+		}
+	}
+	return false
+}
+
+// SelectGrabs return the registered grabs supporting the id by the priority,
+// the grabs with patterns come before the generic ones of the same priority
+func SelectGrabs(id string) []*GrabRegistration {
+	id = strings.TrimSpace(id)
+	var special, generic []*GrabRegistration
+	for _, r := range RegisteredGrabs() {
+		if !r.Support(id) {
+			continue
+		}
+		if len(r.patterns) != 0 {
+			special = append(special, r)
+		} else {
+			generic = append(generic, r)
+		}
+	}
+	selected := append(special, generic...)
+	sort.SliceStable(selected, func(i, j int) bool {
+		return selected[i].Capability.Priority > selected[j].Capability.Priority
+	})
+	return selected
+}
+
+// routeGrabs return the selected grabs in the order of the names,
+// the grabs not in the names are skipped
+func routeGrabs(selected []*GrabRegistration, names []string) []*GrabRegistration {
+	var list []*GrabRegistration
+	for _, name := range names {
+		for _, r := range selected {
+			if r.Name == name {
+				list = append(list, r)
+				break
+			}
+		}
+	}
+	return list
+}
+
+// RegistryOption add the registered grabs by name,every registered grab is added without names
+func RegistryOption(names ...string) Options {
+	return func(impl *scrapeImpl) {
+		if len(names) == 0 {
+			for _, r := range RegisteredGrabs() {
+				impl.grabs = append(impl.grabs, r.New())
+			}
+			return
+		}
+		for _, name := range names {
+			grab, e := NewRegisteredGrab(name)
+			if e != nil {
+				log.Errorw("registry", "name", name, "error", e)
+				continue
+			}
+			impl.grabs = append(impl.grabs, grab)
+		}
+	}
+}
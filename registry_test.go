@@ -0,0 +1,187 @@
+package scrape
+
+import "testing"
+
+// TestRegisterGrab ...
+func TestRegisterGrab(t *testing.T) {
+	testCache(t)
+	if _, b := LookupGrab("javbus"); !b {
+		t.Fatal("javbus is not registered")
+	}
+	e := RegisterGrab("test-fc2", GrabCapability{Amateur: true, Patterns: []string{`^FC2`}}, func() IGrab {
+		return &searchGrabTest{name: "test-fc2"}
+	})
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer UnregisterGrab("test-fc2")
+	if e := RegisterGrab("test-wrong", GrabCapability{Patterns: []string{`(`}}, nil); e == nil {
+		t.Fatal("wrong pattern is registered")
+	}
+
+	selected := SelectGrabs("fc2-ppv-1234567")
+	names := make(map[string]int)
+	for i, r := range selected {
+		names[r.Name] = i
+	}
+	if _, b := names["javbus"]; b {
+		t.Fatal("javbus is selected for fc2")
+	}
+	if i, b := names["test-fc2"]; !b || i > names["javdb"] {
+		t.Fatal("the grabs with patterns come first", names)
+	}
+	for _, r := range SelectGrabs("ABP-891") {
+		if r.Name == "test-fc2" {
+			t.Fatal("fc2 grab is selected for ABP-891")
+		}
+	}
+	grab, e := NewRegisteredGrab("test-fc2")
+	if e != nil || grab.Name() != "test-fc2" {
+		t.Fatal(grab, e)
+	}
+	if _, e := NewRegisteredGrab("unknown"); e == nil {
+		t.Fatal("unknown grab is created")
+	}
+}
+
+// TestRegisterGrabDefinition ...
+func TestRegisterGrabDefinition(t *testing.T) {
+	testCache(t)
+	def, e := ParseGrabDefinition([]byte(testDefinition + "\ncapability:\n  censored: true\n  plot: true\n"))
+	if e != nil {
+		t.Fatal(e)
+	}
+	if e := RegisterGrabDefinition(def); e != nil {
+		t.Fatal(e)
+	}
+	defer UnregisterGrab(def.Name)
+	r, b := LookupGrab(def.Name)
+	if !b || !r.Capability.Censored || !r.Capability.Plot || !r.Capability.SupportLanguage(LanguageEnglish) {
+		t.Fatalf("%+v", r)
+	}
+	if r.Capability.SupportLanguage(LanguageJapanese) {
+		t.Fatal("japanese is not in the definition")
+	}
+}
+
+type routeGrabTest struct {
+	searchGrabTest
+	content Content
+	calls   *int
+}
+
+func (g *routeGrabTest) Clone() IGrab {
+	clone := *g
+	return &clone
+}
+
+func (g *routeGrabTest) Find(string) (IGrab, error) {
+	*g.calls++
+	return g, nil
+}
+
+func (g *routeGrabTest) Result() ([]Content, error) {
+	return []Content{g.content}, nil
+}
+
+// TestRoute ...
+func TestRoute(t *testing.T) {
+	testCache(t)
+	calls := make(map[string]*int)
+	var opts []Options
+	register := func(name string, content Content) {
+		calls[name] = new(int)
+		content.ID, content.From = "RTE-001", name
+		grab := &routeGrabTest{searchGrabTest: searchGrabTest{name: name}, content: content, calls: calls[name]}
+		//the registry only matches,the route finds with the configured grab
+		e := RegisterGrab(name, GrabCapability{Censored: true, Patterns: []string{`^RTE-`}}, func() IGrab {
+			return &routeGrabTest{searchGrabTest: searchGrabTest{name: name}, content: Content{ID: "RTE-001", From: "registry"}, calls: new(int)}
+		})
+		if e != nil {
+			t.Fatal(e)
+		}
+		opts = append(opts, GrabOption(grab))
+	}
+	register("route-a", Content{Poster: "poster"})
+	register("route-b", Content{Actors: []*Star{{Name: "actor"}}})
+	register("route-c", Content{Title: "title"})
+	defer func() {
+		for name := range calls {
+			UnregisterGrab(name)
+		}
+	}()
+
+	impl := NewScrape(append(opts, AutoGrabOption(true))...).(*scrapeImpl)
+	cctx := make(chan Content)
+	go impl.route("RTE-001", cctx)
+	var from []string
+	for c := range cctx {
+		from = append(from, c.From)
+	}
+	if len(from) != 2 || from[0] != "route-a" || from[1] != "route-b" || *calls["route-c"] != 0 {
+		t.Fatal(from, *calls["route-c"])
+	}
+
+	impl = NewScrape(GrabOption(impl.grabs[1]), AutoGrabOption(true)).(*scrapeImpl)
+	cctx = make(chan Content)
+	go impl.route("RTE-001", cctx)
+	from = nil
+	for c := range cctx {
+		from = append(from, c.From)
+	}
+	if len(from) != 1 || from[0] != "route-b" {
+		t.Fatal("only the configured grabs are routed", from)
+	}
+}
+
+// TestRoutePriority ...
+func TestRoutePriority(t *testing.T) {
+	testCache(t)
+	calls := make(map[string]*int)
+	var opts []Options
+	register := func(name string, priority int, patterns ...string) {
+		calls[name] = new(int)
+		grab := &routeGrabTest{searchGrabTest: searchGrabTest{name: name}, content: Content{ID: "RTP-001", From: name}, calls: calls[name]}
+		capability := GrabCapability{Censored: true, Amateur: true, Priority: priority, Patterns: patterns}
+		if e := RegisterGrab(name, capability, func() IGrab { return grab }); e != nil {
+			t.Fatal(e)
+		}
+		opts = append(opts, GrabOption(grab))
+	}
+	register("priority-low", 0, `^RTP-`)
+	register("priority-high", 10, `^RTP-`)
+	register("priority-fc2", 0, `^FC2`)
+	defer func() {
+		for name := range calls {
+			UnregisterGrab(name)
+		}
+	}()
+	var names []string
+	for _, r := range SelectGrabs("RTP-001") {
+		names = append(names, r.Name)
+	}
+	if len(names) < 2 || names[0] != "priority-high" || names[1] != "priority-low" {
+		t.Fatal("the higher priority comes first", names)
+	}
+
+	routed := func(impl *scrapeImpl, id string) []string {
+		cctx := make(chan Content)
+		go impl.route(id, cctx)
+		var from []string
+		for c := range cctx {
+			from = append(from, c.From)
+		}
+		return from
+	}
+	impl := NewScrape(append(opts, AutoGrabOption(true), RouteRequiredOption(RefreshPlot),
+		RouteOption(IDCensored, "priority-low", "priority-high"),
+		RouteOption(IDFC2, "priority-fc2"))...).(*scrapeImpl)
+	from := routed(impl, "RTP-001")
+	if len(from) != 2 || from[0] != "priority-low" || from[1] != "priority-high" {
+		t.Fatal("the configured order is used", from)
+	}
+	from = routed(impl, "FC2-PPV-1234567")
+	if len(from) != 1 || from[0] != "priority-fc2" {
+		t.Fatal("only the configured grabs are routed", from)
+	}
+}
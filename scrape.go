@@ -1,6 +1,8 @@
 package scrape
 
 import (
+	"context"
+	"errors"
 	"strings"
 	"sync"
 
@@ -14,10 +16,13 @@ type RangeFunc func(key string, content Content) error
 // IScrape ...
 type IScrape interface {
 	Cache() *Cache
+	Grabs() []IGrab
 	Force(b bool)
 	IsGrabSample() (b bool)
 	Find(name string) (e error)
+	Search(ctx context.Context, query string, limit int) <-chan Content
 	Clear()
+	Clone() IScrape
 	Range(rangeFunc RangeFunc) error
 	OutputCallback(f func(key string, content Content) *OutputInfo) []OutputInfo
 	Output() error
@@ -26,12 +31,17 @@ type IScrape interface {
 type scrapeImpl struct {
 	contents map[string][]Content
 	grabs    []IGrab
+	plots    []IPlot
+	library  *Library
 	sample   bool
 	cache    *Cache
 	output   string
 	infoName string
 	exact    bool
 	force    bool
+	auto     bool
+	required []RefreshField
+	routes   map[IDKind][]string
 	result   []*OutputInfo
 }
 
@@ -81,6 +91,46 @@ func MergeOptimize(id string, contents []*Content) *Content {
 				log.Infow("optimize", "field", "actor")
 				content.Actors = c.Actors
 			}
+			if content.OriginalTitle == "" && c.OriginalTitle != "" {
+				log.Infow("optimize", "field", "original title")
+				content.OriginalTitle = c.OriginalTitle
+			}
+			if content.Runtime == 0 && c.Runtime != 0 {
+				log.Infow("optimize", "field", "runtime")
+				content.Runtime = c.Runtime
+			}
+			if content.Rating == 0 && c.Rating != 0 {
+				log.Infow("optimize", "field", "rating", "from", c.From)
+				content.Rating = c.Rating
+				content.Reviews = c.Reviews
+			}
+			if len(content.Magnets) < len(c.Magnets) {
+				log.Infow("optimize", "field", "magnet", "from", c.From)
+				content.Magnets = c.Magnets
+			}
+			if content.Preview == "" && c.Preview != "" {
+				log.Infow("optimize", "field", "preview", "from", c.From)
+				content.Preview = c.Preview
+			}
+			if content.Plot == "" && c.Plot != "" {
+				log.Infow("optimize", "field", "plot", "from", c.PlotFrom)
+				content.Plot = c.Plot
+				content.PlotFrom = c.PlotFrom
+			}
+			if len(c.Localized) > 0 {
+				//the map is still shared with the grab result,merge into a copy
+				localized := make(map[string]*Localized, len(content.Localized)+len(c.Localized))
+				for lang, l := range content.Localized {
+					localized[lang] = l
+				}
+				for lang, l := range c.Localized {
+					if _, b := localized[lang]; !b {
+						log.Infow("optimize", "field", "localized", "language", lang)
+						localized[lang] = l
+					}
+				}
+				content.Localized = localized
+			}
 		}
 	}
 	return content
@@ -107,6 +157,32 @@ func GrabOption(grab IGrab) Options {
 	}
 }
 
+// AutoGrabOption route every find to the grabs supporting the id by the registry,
+// the grabs are tried in order until the required fields are filled
+func AutoGrabOption(b bool) Options {
+	return func(impl *scrapeImpl) {
+		impl.auto = b
+	}
+}
+
+// RouteRequiredOption the fields stopping the route when filled
+func RouteRequiredOption(fields ...RefreshField) Options {
+	return func(impl *scrapeImpl) {
+		impl.required = fields
+	}
+}
+
+// RouteOption route the kind of ids to the named grabs only and in order,
+// like javbus then javdb for IDCensored,the other kinds are routed by the registry
+func RouteOption(kind IDKind, names ...string) Options {
+	return func(impl *scrapeImpl) {
+		if impl.routes == nil {
+			impl.routes = make(map[IDKind][]string)
+		}
+		impl.routes[kind] = names
+	}
+}
+
 func ForceOption(b bool) Options {
 	return func(impl *scrapeImpl) {
 		impl.force = b
@@ -126,6 +202,7 @@ func NewScrape(opts ...Options) IScrape {
 		exact:    false,
 		output:   DefaultOutputPath,
 		infoName: DefaultInfoName,
+		required: []RefreshField{RefreshPoster, RefreshActors},
 	}
 
 	for _, opt := range opts {
@@ -146,10 +223,22 @@ func (impl *scrapeImpl) Clear() {
 	impl.contents = make(map[string][]Content)
 }
 
+// Clone a scrape with the same options and copies of the grabs,the results are not shared
+func (impl *scrapeImpl) Clone() IScrape {
+	clone := *impl
+	clone.contents = make(map[string][]Content)
+	clone.result = nil
+	clone.grabs = make([]IGrab, len(impl.grabs))
+	for i, grab := range impl.grabs {
+		clone.grabs[i] = cloneGrab(grab)
+	}
+	return &clone
+}
+
 // Output ...
 func (impl *scrapeImpl) Output() error {
 	return impl.Range(func(key string, content Content) (e error) {
-		e = copyInfo(&content, DefaultOutputPath, DefaultInfoName)
+		e = copyInfo(&content, DefaultOutputPath, DefaultInfoName, PolicyFillEmpty)
 		if e != nil {
 			log.Errorw("copy info", "error", e, "output", key)
 		}
@@ -171,7 +260,7 @@ func (impl scrapeImpl) OutputCallback(f func(key string, content Content) *Outpu
 		if option.Name == "" {
 			option.Name = key
 		}
-		err := copyFileWithInfo(impl.Cache(), content, option)
+		err := copyFileWithInfo(impl.Cache(), content, option, impl.contents[key]...)
 		if debug {
 			log.Infow("append info", "skip", option.Skip, "err", err)
 		}
@@ -195,6 +284,11 @@ func (impl *scrapeImpl) Cache() *Cache {
 	return impl.cache
 }
 
+// Grabs ...
+func (impl *scrapeImpl) Grabs() []IGrab {
+	return impl.grabs
+}
+
 // Range ...
 func (impl *scrapeImpl) Range(rangeFunc RangeFunc) error {
 	for key, value := range impl.contents {
@@ -214,43 +308,21 @@ func (impl *scrapeImpl) Range(rangeFunc RangeFunc) error {
 
 // Find ...
 func (impl *scrapeImpl) Find(name string) (e error) {
+	found := make(map[string][]Content)
 	chanContent := make(chan Content, 1)
-	wg := &sync.WaitGroup{}
-	for _, grab := range impl.grabs {
-		wg.Add(1)
-		go func(grab IGrab, exact bool, force bool, sample bool, cctx chan<- Content) {
-			defer wg.Done()
-			grab.SetExact(exact)
-			grab.SetSample(sample)
-			grab.SetForce(force)
-			iGrab, e := grab.Find(name)
-			if e != nil {
-				log.Errorw("error", "error", e, "name", grab.Name(), "find", name)
-				return
-			}
-			cs, e := iGrab.Result()
-			if e != nil {
-				log.Errorw("error", "error", e, "name", grab.Name(), "decode", name)
-			}
-			if debug {
-				log.Infow("find", "result", cs)
-			}
-			for _, c := range cs {
-				cctx <- c
-			}
-		}(grab, impl.exact, impl.force, impl.sample, chanContent)
+	if impl.auto {
+		go impl.route(name, chanContent)
+	} else {
+		go impl.findAll(name, chanContent)
 	}
 
-	go func(cctx chan<- Content) {
-		wg.Wait()
-		close(cctx)
-	}(chanContent)
-
 	for content := range chanContent {
+		plotEnrich(impl.plots, &content, impl.force)
 		e = imageCache(impl.cache, content, impl.sample)
 		if e != nil {
 			log.Errorw("error", "cache", content.ID, "error", e)
 		}
+		found[content.ID] = append(found[content.ID], content)
 		if v, b := impl.contents[content.ID]; b {
 			impl.contents[content.ID] = append(v, content)
 		} else {
@@ -260,10 +332,116 @@ func (impl *scrapeImpl) Find(name string) (e error) {
 			log.Infow("find", "content", content)
 		}
 	}
+	if impl.library != nil {
+		librarySave(impl.library, found)
+	}
 
 	return nil
 }
 
+// findAll find the name with every grab at the same time,
+// the grabs are shared with the other finds so a copy is set up for this one
+func (impl *scrapeImpl) findAll(name string, cctx chan<- Content) {
+	wg := &sync.WaitGroup{}
+	for _, grab := range impl.grabs {
+		grab = cloneGrab(grab)
+		wg.Add(1)
+		go func(grab IGrab) {
+			defer wg.Done()
+			for _, c := range impl.grabFind(grab, name) {
+				cctx <- c
+			}
+		}(grab)
+	}
+	wg.Wait()
+	close(cctx)
+}
+
+// route find the name with the registered grabs supporting it one by one,
+// stop when the required fields are filled
+func (impl *scrapeImpl) route(name string, cctx chan<- Content) {
+	defer close(cctx)
+	var contents []*Content
+	for _, grab := range impl.routeGrabs(name) {
+		cs := impl.grabFind(grab, name)
+		for _, c := range cs {
+			cctx <- c
+			copied := c
+			contents = append(contents, &copied)
+		}
+		if len(cs) == 0 {
+			continue
+		}
+		merged := MergeOptimize(cs[0].ID, contents)
+		if merged != nil && impl.filled(merged) {
+			log.Infow("route", "id", name, "filled", grab.Name())
+			return
+		}
+	}
+}
+
+// routeGrabs return the copies of the grabs supporting the name in the route order,
+// the registry only matches the grabs by name so the options of the grabs are kept,
+// the unregistered grabs come last unless the kind of the name is configured
+func (impl *scrapeImpl) routeGrabs(name string) []IGrab {
+	selected := SelectGrabs(name)
+	names, configured := impl.routes[ClassifyID(name)]
+	if configured {
+		selected = routeGrabs(selected, names)
+	}
+	var grabs []IGrab
+	for _, r := range selected {
+		for _, grab := range impl.grabs {
+			if grab.Name() == r.Name {
+				grabs = append(grabs, cloneGrab(grab))
+				break
+			}
+		}
+	}
+	if configured {
+		return grabs
+	}
+	for _, grab := range impl.grabs {
+		if _, b := LookupGrab(grab.Name()); !b {
+			grabs = append(grabs, cloneGrab(grab))
+		}
+	}
+	return grabs
+}
+
+// filled check the required fields of the route are filled
+func (impl *scrapeImpl) filled(content *Content) bool {
+	for _, field := range impl.required {
+		if missingField(content, field) {
+			return false
+		}
+	}
+	return true
+}
+
+func (impl *scrapeImpl) grabFind(grab IGrab, name string) []Content {
+	grab.SetExact(impl.exact)
+	grab.SetSample(impl.sample)
+	grab.SetForce(impl.force)
+	iGrab, e := grab.Find(name)
+	if errors.Is(e, ErrBlocked) {
+		log.Warnw("blocked", "error", e, "name", grab.Name(), "find", name)
+		return nil
+	}
+	if e != nil {
+		log.Errorw("error", "error", e, "name", grab.Name(), "find", name)
+		return nil
+	}
+	cs, e := iGrab.Result()
+	if e != nil {
+		log.Errorw("error", "error", e, "name", grab.Name(), "decode", name)
+	}
+	if debug {
+		log.Infow("find", "result", cs)
+	}
+	return cs
+}
+
 func (impl *scrapeImpl) init() {
 	if impl.cache == nil {
 		impl.cache = NewCache()
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"path/filepath"
+	"sync"
 	"testing"
 )
 
@@ -18,6 +19,7 @@ func init() {
 func TestNewScrape(t *testing.T) {
 	var e error
 	DebugOn()
+	testClient(t)
 	e = RegisterProxy("http://localhost:7890")
 	if e != nil {
 		return
@@ -57,6 +59,7 @@ func TestNewScrape(t *testing.T) {
 func TestNewScrapeOutput(t *testing.T) {
 	var e error
 	DebugOn()
+	testClient(t)
 	e = RegisterProxy("http://localhost:7890")
 	if e != nil {
 		return
@@ -109,8 +112,78 @@ func TestNewScrapeOutput(t *testing.T) {
 	//scrape.Clear()
 }
 
+// TestMergeOptimizeLocalized ...
+func TestMergeOptimizeLocalized(t *testing.T) {
+	source := map[string]*Localized{"ja": {Language: "ja", Title: "ja"}}
+	merged := MergeOptimize("ABC-001", []*Content{
+		{ID: "ABC-001", Localized: source},
+		{ID: "abc-001", Localized: map[string]*Localized{"en": {Language: "en", Title: "en"}}},
+	})
+	if len(merged.Localized) != 2 {
+		t.Fatal(merged.Localized)
+	}
+	if len(source) != 1 {
+		t.Fatal("the localized map of the source is changed")
+	}
+}
+
 func checkErr(err error) {
 	if err != nil {
 		log.Fatal(err)
 	}
 }
+
+type stateGrabTest struct {
+	searchGrabTest
+	exact  bool
+	finder string
+}
+
+func (g *stateGrabTest) SetExact(b bool) {
+	g.exact = b
+}
+
+func (g *stateGrabTest) Clone() IGrab {
+	clone := *g
+	return &clone
+}
+
+func (g *stateGrabTest) Find(name string) (IGrab, error) {
+	g.finder = name
+	return &searchGrabTest{name: g.name, page: 1, pages: 1}, nil
+}
+
+// plainGrabTest a grab of another package without Clone
+type plainGrabTest struct {
+	IGrab
+}
+
+// TestFindConcurrent ...
+func TestFindConcurrent(t *testing.T) {
+	testCache(t)
+	plain := plainGrabTest{&searchGrabTest{name: "plain", pages: 1}}
+	if cloneGrab(plain) != IGrab(plain) {
+		t.Fatal("the grab without Clone is used as it is")
+	}
+	impl := NewScrape(GrabOption(&stateGrabTest{searchGrabTest: searchGrabTest{name: "state"}}), GrabOption(plain), SampleOption(false)).(*scrapeImpl)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			clone := impl.Clone()
+			if e := clone.Find(fmt.Sprintf("ABC-%03d", i)); e != nil {
+				t.Error(e)
+			}
+			//the same scrape finds at the same time too
+			cctx := make(chan Content)
+			go impl.findAll(fmt.Sprintf("ABC-%03d", i), cctx)
+			for range cctx {
+			}
+		}(i)
+	}
+	wg.Wait()
+	if grab := impl.grabs[0].(*stateGrabTest); grab.finder != "" {
+		t.Fatal("the shared grab is changed", grab.finder)
+	}
+}
@@ -0,0 +1,95 @@
+package scrape
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/goextension/log"
+)
+
+// Search page through the search listing of every grab,
+// results are deduplicated by id and the stream is closed after limit results(limit <= 0 means no limit)
+// or when the context is done
+func (impl *scrapeImpl) Search(ctx context.Context, query string, limit int) <-chan Content {
+	ctx, cancel := context.WithCancel(ctx)
+	out := make(chan Content)
+	contents := make(chan Content)
+	wg := &sync.WaitGroup{}
+	for _, grab := range impl.grabs {
+		//the grabs are shared with the other calls,set up a copy for this search
+		grab = cloneGrab(grab)
+		grab.SetExact(false)
+		grab.SetSample(impl.sample)
+		grab.SetForce(impl.force)
+		wg.Add(1)
+		go func(grab IGrab) {
+			defer wg.Done()
+			e := searchGrab(ctx, grab, query, contents)
+			if e != nil {
+				log.Errorw("search", "name", grab.Name(), "query", query, "error", e)
+			}
+		}(grab)
+	}
+
+	go func() {
+		wg.Wait()
+		close(contents)
+	}()
+
+	go func() {
+		defer close(out)
+		defer cancel()
+		ids := make(map[string]bool)
+		count := 0
+		for content := range contents {
+			if ctx.Err() != nil {
+				continue
+			}
+			id := strings.ToUpper(content.ID)
+			if id == "" || ids[id] {
+				continue
+			}
+			ids[id] = true
+			select {
+			case out <- content:
+			case <-ctx.Done():
+				continue
+			}
+			count++
+			if limit > 0 && count >= limit {
+				cancel()
+			}
+		}
+	}()
+	return out
+}
+
+// searchGrab send every result of every page until there is no next page or the context is done
+func searchGrab(ctx context.Context, grab IGrab, query string, contents chan<- Content) error {
+	iGrab, e := grab.Find(query)
+	for page := 1; ; page++ {
+		if e != nil {
+			return e
+		}
+		var cs []Content
+		cs, e = iGrab.Result()
+		if e != nil {
+			return e
+		}
+		if debug {
+			log.Infow("search", "name", grab.Name(), "page", page, "size", len(cs))
+		}
+		for _, c := range cs {
+			select {
+			case contents <- c:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+		if ctx.Err() != nil || !iGrab.HasNext() {
+			return nil
+		}
+		iGrab, e = iGrab.Next()
+	}
+}
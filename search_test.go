@@ -0,0 +1,91 @@
+package scrape
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type searchGrabTest struct {
+	name  string
+	page  int
+	pages int
+}
+
+func (g *searchGrabTest) MainPage(url string)               {}
+func (g *searchGrabTest) SetSample(bool)                    {}
+func (g *searchGrabTest) SetExact(bool)                     {}
+func (g *searchGrabTest) SetLanguage(language GrabLanguage) {}
+func (g *searchGrabTest) SetForce(force bool)               {}
+func (g *searchGrabTest) Name() string                      { return g.name }
+func (g *searchGrabTest) HasNext() bool                     { return g.page < g.pages }
+
+func (g *searchGrabTest) Clone() IGrab {
+	clone := *g
+	return &clone
+}
+
+func (g *searchGrabTest) Find(string) (IGrab, error) {
+	return &searchGrabTest{name: g.name, page: 1, pages: g.pages}, nil
+}
+
+func (g *searchGrabTest) Next() (IGrab, error) {
+	if !g.HasNext() {
+		return nil, errors.New("no next page")
+	}
+	return &searchGrabTest{name: g.name, page: g.page + 1, pages: g.pages}, nil
+}
+
+func (g *searchGrabTest) Result() ([]Content, error) {
+	var cs []Content
+	for i := 0; i < 2; i++ {
+		cs = append(cs, Content{From: g.name, ID: fmt.Sprintf("ABC-%03d", (g.page-1)*2+i)})
+	}
+	return cs, nil
+}
+
+// TestSearch ...
+func TestSearch(t *testing.T) {
+	impl := &scrapeImpl{grabs: []IGrab{
+		&searchGrabTest{name: "a", pages: 3},
+		&searchGrabTest{name: "b", pages: 3},
+	}}
+	ids := make(map[string]bool)
+	for c := range impl.Search(context.Background(), "abc", 0) {
+		if ids[c.ID] {
+			t.Fatalf("duplicate id %s", c.ID)
+		}
+		ids[c.ID] = true
+	}
+	if len(ids) != 6 {
+		t.Fatalf("got %d results want 6", len(ids))
+	}
+
+	count := 0
+	for range impl.Search(context.Background(), "abc", 4) {
+		count++
+	}
+	if count != 4 {
+		t.Fatalf("got %d results want 4", count)
+	}
+
+	//the producers stop when the caller is gone
+	ctx, cancel := context.WithCancel(context.Background())
+	impl.grabs = append(impl.grabs, &searchGrabTest{name: "c", pages: 1 << 20})
+	out := impl.Search(ctx, "abc", 0)
+	<-out
+	cancel()
+	done := make(chan struct{})
+	go func() {
+		for range out {
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the search is not stopped by the context")
+	}
+}
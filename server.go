@@ -0,0 +1,393 @@
+package scrape
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goextension/log"
+)
+
+// DefaultServerMaxAge the max age of the scraped results in seconds
+var DefaultServerMaxAge = 3600
+
+// ErrNotFound ...
+var ErrNotFound = errors.New("not found")
+
+// DefaultServerJobTTL the finished jobs are removed after the duration
+var DefaultServerJobTTL = time.Hour
+
+// DefaultServerSearchLimit ...
+var DefaultServerSearchLimit = 20
+
+// JobStatus ...
+type JobStatus string
+
+// JobStatus detail ...
+const (
+	JobPending JobStatus = "pending"
+	JobRunning JobStatus = "running"
+	JobDone    JobStatus = "done"
+)
+
+// Job an async batch scrape
+type Job struct {
+	ID       string
+	Status   JobStatus
+	IDs      []string
+	Finished int
+	Results  map[string]*Content
+	Errors   map[string]string
+	Created  time.Time
+	Updated  time.Time
+}
+
+// Server serve the scrape results with a http json api
+type Server struct {
+	scrape   IScrape
+	mux      *http.ServeMux
+	jobs     map[string]*Job
+	jobID    int
+	jellyfin bool
+	jobLock  sync.Mutex
+	jobTTL   time.Duration
+	maxAge   int
+}
+
+// ServerOptions ...
+type ServerOptions func(s *Server)
+
+// ServerMaxAge ...
+func ServerMaxAge(sec int) ServerOptions {
+	return func(s *Server) {
+		s.maxAge = sec
+	}
+}
+
+// ServerJobTTL ...
+func ServerJobTTL(ttl time.Duration) ServerOptions {
+	return func(s *Server) {
+		s.jobTTL = ttl
+	}
+}
+
+// NewServer ...
+func NewServer(scrape IScrape, opts ...ServerOptions) *Server {
+	s := &Server{
+		scrape: scrape,
+		mux:    http.NewServeMux(),
+		jobs:   make(map[string]*Job),
+		jobTTL: DefaultServerJobTTL,
+		maxAge: DefaultServerMaxAge,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.mux.HandleFunc("/movies/", s.handleMovie)
+	s.mux.HandleFunc("/search", s.handleSearch)
+	s.mux.HandleFunc("/actors/", s.handleActor)
+	s.mux.HandleFunc("/images/", s.handleImage)
+	s.mux.HandleFunc("/jobs", s.handleJobs)
+	s.mux.HandleFunc("/jobs/", s.handleJob)
+	if s.jellyfin {
+		s.registerJellyfin()
+	}
+	return s
+}
+
+// Handle add another handler to the server
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// ServeHTTP ...
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if debug {
+		log.Infow("serve", "method", r.Method, "url", r.URL.String())
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// ListenAndServe ...
+func (s *Server) ListenAndServe(addr string) error {
+	return http.ListenAndServe(addr, s)
+}
+
+// Movie scrape the id and merge the results of every grab,
+// every call finds with its own copy of the scrape so the requests run at the same time
+func (s *Server) Movie(id string) (*Content, error) {
+	scrape := s.scrape.Clone()
+	e := scrape.Find(id)
+	if e != nil {
+		return nil, e
+	}
+	var contents []*Content
+	e = scrape.Range(func(key string, content Content) error {
+		c := content
+		contents = append(contents, &c)
+		return nil
+	})
+	if e != nil {
+		return nil, e
+	}
+	content := MergeOptimize(id, contents)
+	if content == nil {
+		return nil, ErrNotFound
+	}
+	return content, nil
+}
+
+func (s *Server) handleMovie(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		serverError(w, http.StatusMethodNotAllowed, errors.New(r.Method+" is not allowed"))
+		return
+	}
+	id := strings.TrimPrefix(r.URL.Path, "/movies/")
+	if id == "" {
+		serverError(w, http.StatusBadRequest, errors.New("id is empty"))
+		return
+	}
+	content, e := s.Movie(id)
+	if e != nil {
+		serverError(w, serverStatus(e), e)
+		return
+	}
+	s.writeCached(w, r, content)
+}
+
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("q")
+	if query == "" {
+		serverError(w, http.StatusBadRequest, errors.New("q is empty"))
+		return
+	}
+	limit := DefaultServerSearchLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		var e error
+		limit, e = strconv.Atoi(v)
+		if e != nil || limit <= 0 {
+			serverError(w, http.StatusBadRequest, errors.New("wrong limit "+v))
+			return
+		}
+	}
+	contents := []Content{}
+	for content := range s.scrape.Search(r.Context(), query, limit) {
+		contents = append(contents, content)
+	}
+	s.writeCached(w, r, contents)
+}
+
+// actorResult ...
+type actorResult struct {
+	Actor string
+	IDs   []string
+}
+
+func (s *Server) handleActor(w http.ResponseWriter, r *http.Request) {
+	actor := strings.TrimPrefix(r.URL.Path, "/actors/")
+	if actor == "" {
+		serverError(w, http.StatusBadRequest, errors.New("actor is empty"))
+		return
+	}
+	result := &actorResult{Actor: actor}
+	var set idSet
+	for _, grab := range s.scrape.Grabs() {
+		browse, b := grab.(IBrowse)
+		if !b {
+			continue
+		}
+		//a failed page still returns the ids of the pages before
+		ids, e := browse.ListByActor(actor)
+		if e != nil {
+			log.Errorw("serve", "name", grab.Name(), "actor", actor, "error", e)
+		}
+		set.add(ids...)
+	}
+	result.IDs = set.ids
+	if len(result.IDs) == 0 {
+		serverError(w, http.StatusNotFound, ErrNotFound)
+		return
+	}
+	s.writeCached(w, r, result)
+}
+
+func (s *Server) handleImage(w http.ResponseWriter, r *http.Request) {
+	hash := strings.TrimPrefix(r.URL.Path, "/images/")
+	if !hashRegexp.MatchString(hash) {
+		serverError(w, http.StatusNotFound, ErrNotFound)
+		return
+	}
+	etag := `"` + hash + `"`
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	//the pages are cached by hash too,only the images are served
+	bys, e := s.scrape.Cache().GetHash(hash)
+	if e == nil {
+		e = ValidateImage(bys, "", "")
+	}
+	if e != nil {
+		serverError(w, http.StatusNotFound, ErrNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", http.DetectContentType(bys))
+	w.Header().Set("Content-Length", strconv.Itoa(len(bys)))
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.Header().Set("ETag", etag)
+	_, _ = w.Write(bys)
+}
+
+// jobRequest ...
+type jobRequest struct {
+	IDs []string
+}
+
+func (s *Server) handleJobs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		serverError(w, http.StatusMethodNotAllowed, errors.New(r.Method+" is not allowed"))
+		return
+	}
+	var req jobRequest
+	e := json.NewDecoder(r.Body).Decode(&req)
+	if e != nil {
+		serverError(w, http.StatusBadRequest, e)
+		return
+	}
+	if len(req.IDs) == 0 {
+		serverError(w, http.StatusBadRequest, errors.New("ids is empty"))
+		return
+	}
+	job := s.newJob(req.IDs)
+	go s.runJob(job)
+	w.Header().Set("Location", "/jobs/"+job.ID)
+	serverJSON(w, http.StatusAccepted, s.copyJob(job))
+}
+
+func (s *Server) handleJob(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/jobs/")
+	s.jobLock.Lock()
+	s.pruneJobs(time.Now())
+	job, b := s.jobs[id]
+	s.jobLock.Unlock()
+	if !b {
+		serverError(w, http.StatusNotFound, ErrNotFound)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-cache")
+	serverJSON(w, http.StatusOK, s.copyJob(job))
+}
+
+func (s *Server) newJob(ids []string) *Job {
+	s.jobLock.Lock()
+	defer s.jobLock.Unlock()
+	s.pruneJobs(time.Now())
+	s.jobID++
+	job := &Job{
+		ID:      strconv.Itoa(s.jobID),
+		Status:  JobPending,
+		IDs:     ids,
+		Results: make(map[string]*Content),
+		Errors:  make(map[string]string),
+		Created: time.Now(),
+		Updated: time.Now(),
+	}
+	s.jobs[job.ID] = job
+	return job
+}
+
+// pruneJobs remove the jobs finished before the ttl,the caller holds the job lock
+func (s *Server) pruneJobs(now time.Time) {
+	for id, job := range s.jobs {
+		if job.Status == JobDone && now.Sub(job.Updated) > s.jobTTL {
+			delete(s.jobs, id)
+		}
+	}
+}
+
+func (s *Server) copyJob(job *Job) Job {
+	s.jobLock.Lock()
+	defer s.jobLock.Unlock()
+	c := *job
+	c.Results = make(map[string]*Content, len(job.Results))
+	for k, v := range job.Results {
+		c.Results[k] = v
+	}
+	c.Errors = make(map[string]string, len(job.Errors))
+	for k, v := range job.Errors {
+		c.Errors[k] = v
+	}
+	return c
+}
+
+func (s *Server) runJob(job *Job) {
+	s.updateJob(job, func(job *Job) {
+		job.Status = JobRunning
+	})
+	for _, id := range job.IDs {
+		content, e := s.Movie(id)
+		s.updateJob(job, func(job *Job) {
+			job.Finished++
+			if e != nil {
+				job.Errors[id] = e.Error()
+				return
+			}
+			job.Results[id] = content
+		})
+	}
+	s.updateJob(job, func(job *Job) {
+		job.Status = JobDone
+	})
+}
+
+func (s *Server) updateJob(job *Job, f func(job *Job)) {
+	s.jobLock.Lock()
+	defer s.jobLock.Unlock()
+	f(job)
+	job.Updated = time.Now()
+}
+
+// writeCached write the json with the cache headers,a matched etag returns not modified
+func (s *Server) writeCached(w http.ResponseWriter, r *http.Request, v interface{}) {
+	bys, e := json.Marshal(v)
+	if e != nil {
+		serverError(w, http.StatusInternalServerError, e)
+		return
+	}
+	etag := fmt.Sprintf(`"%x"`, sha256.Sum256(bys))
+	w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(s.maxAge))
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_, _ = w.Write(bys)
+}
+
+func serverStatus(e error) int {
+	if errors.Is(e, ErrNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusBadGateway
+}
+
+func serverJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	e := json.NewEncoder(w).Encode(v)
+	if e != nil {
+		log.Errorw("serve", "error", e)
+	}
+}
+
+func serverError(w http.ResponseWriter, status int, e error) {
+	w.Header().Set("Cache-Control", "no-store")
+	serverJSON(w, status, map[string]string{"error": e.Error()})
+}
@@ -0,0 +1,222 @@
+package scrape
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newServerTest() *Server {
+	return NewServer(&scrapeImpl{
+		contents: make(map[string][]Content),
+		grabs:    []IGrab{&searchGrabTest{name: "test", pages: 2}},
+	})
+}
+
+// TestServerMovie ...
+func TestServerMovie(t *testing.T) {
+	s := newServerTest()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/movies/ABC-001", nil))
+	if w.Code != http.StatusOK {
+		t.Fatal(w.Code, w.Body.String())
+	}
+	var content Content
+	if e := json.Unmarshal(w.Body.Bytes(), &content); e != nil || content.ID != "ABC-001" {
+		t.Fatal(content, e)
+	}
+	etag := w.Header().Get("ETag")
+	if etag == "" || w.Header().Get("Cache-Control") == "" {
+		t.Fatal("no cache headers")
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/movies/ABC-001", nil)
+	r.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	s.ServeHTTP(w, r)
+	if w.Code != http.StatusNotModified {
+		t.Fatal(w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/movies/XYZ-001", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatal(w.Code)
+	}
+}
+
+// TestServerMovieConcurrent ...
+func TestServerMovieConcurrent(t *testing.T) {
+	s := newServerTest()
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			content, e := s.Movie(id)
+			if e != nil || content.ID != id {
+				t.Error(id, content, e)
+			}
+		}(fmt.Sprintf("ABC-%03d", i%2))
+	}
+	wg.Wait()
+}
+
+// TestServerSearch ...
+func TestServerSearch(t *testing.T) {
+	s := newServerTest()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/search?q=abc&limit=3", nil))
+	var contents []Content
+	if e := json.Unmarshal(w.Body.Bytes(), &contents); e != nil || len(contents) != 3 {
+		t.Fatal(w.Body.String(), e)
+	}
+}
+
+// TestServerJob ...
+func TestServerJob(t *testing.T) {
+	s := newServerTest()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/jobs", strings.NewReader(`{"IDs":["ABC-000","ABC-001"]}`)))
+	if w.Code != http.StatusAccepted {
+		t.Fatal(w.Code, w.Body.String())
+	}
+	location := w.Header().Get("Location")
+	for i := 0; i < 100; i++ {
+		w = httptest.NewRecorder()
+		s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, location, nil))
+		var job Job
+		if e := json.Unmarshal(w.Body.Bytes(), &job); e != nil {
+			t.Fatal(e)
+		}
+		if job.Status == JobDone {
+			if len(job.Results) != 2 {
+				t.Fatal(job)
+			}
+			//the finished job is removed after the ttl
+			s.jobTTL = time.Millisecond
+			time.Sleep(2 * time.Millisecond)
+			w = httptest.NewRecorder()
+			s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, location, nil))
+			if w.Code != http.StatusNotFound || len(s.jobs) != 0 {
+				t.Fatal(w.Code, s.jobs)
+			}
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("job is not done")
+}
+
+// TestServerJellyfin ...
+func TestServerJellyfin(t *testing.T) {
+	s := NewServer(&scrapeImpl{
+		contents: make(map[string][]Content),
+		grabs:    []IGrab{&searchGrabTest{name: "test", pages: 1}},
+	}, ServerJellyfin(true))
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/jellyfin/search?name=abc-001.mp4", nil))
+	var results []*JellyfinSearchResult
+	if e := json.Unmarshal(w.Body.Bytes(), &results); e != nil || len(results) != 1 {
+		t.Fatal(w.Body.String(), e)
+	}
+	if results[0].ProviderIds[JellyfinProviderName] != "ABC-001" {
+		t.Fatal(results[0])
+	}
+
+	w = httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/jellyfin/movies/ABC-001/images", nil))
+	if w.Code != http.StatusOK {
+		t.Fatal(w.Code, w.Body.String())
+	}
+}
+
+// TestServerImage ...
+func TestServerImage(t *testing.T) {
+	c := testCache(t)
+	img := validateTestImage(t, 100, 100)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/image.png" {
+			_, _ = w.Write(img)
+			return
+		}
+		fmt.Fprint(w, "<html>page</html>")
+	}))
+	defer server.Close()
+	if _, e := c.GetImage(server.URL+"/image.png", true); e != nil {
+		t.Fatal(e)
+	}
+	if _, e := c.ForceGet(server.URL + "/page"); e != nil {
+		t.Fatal(e)
+	}
+	if e := c.SetCookies("javdb.com", &http.Cookie{Name: "remember_me_token", Value: "secret"}); e != nil {
+		t.Fatal(e)
+	}
+
+	s := NewServer(NewScrape(CacheOption(c)))
+	for path, code := range map[string]int{
+		"/images/" + Hash(server.URL+"/image.png"): http.StatusOK,
+		"/images/" + Hash(server.URL+"/page"):      http.StatusNotFound,
+		"/images/" + cookieJarKey:                  http.StatusNotFound,
+		"/images/" + Hash("unknown"):               http.StatusNotFound,
+	} {
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != code || strings.Contains(w.Body.String(), "secret") {
+			t.Errorf("%s: got %d want %d", path, w.Code, code)
+		}
+		if code != http.StatusOK && strings.Contains(w.Header().Get("Cache-Control"), "immutable") {
+			t.Errorf("%s: the error is cached", path)
+		}
+	}
+}
+
+// TestServerJellyfinThumb ...
+func TestServerJellyfinThumb(t *testing.T) {
+	c := testCache(t)
+	img := validateTestImage(t, 100, 100)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/thumb.png" {
+			_, _ = w.Write(img)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	calls := 0
+	s := NewServer(NewScrape(CacheOption(c),
+		GrabOption(&routeGrabTest{searchGrabTest: searchGrabTest{name: "a"}, content: Content{ID: "ABC-001", Thumb: server.URL + "/thumb.png"}, calls: &calls}),
+		GrabOption(&routeGrabTest{searchGrabTest: searchGrabTest{name: "b"}, content: Content{ID: "ABC-002", Thumb: server.URL + "/missing.png"}, calls: &calls}),
+	), ServerJellyfin(true))
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/jellyfin/search?name=abc", nil))
+	var results []*JellyfinSearchResult
+	if e := json.Unmarshal(w.Body.Bytes(), &results); e != nil || len(results) != 2 {
+		t.Fatal(w.Body.String(), e)
+	}
+	for _, result := range results {
+		switch result.ProviderIds[JellyfinProviderName] {
+		case "ABC-001":
+			u, e := url.Parse(result.ImageUrl)
+			if e != nil || result.ImageUrl == "" {
+				t.Fatal(result.ImageUrl, e)
+			}
+			w := httptest.NewRecorder()
+			s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, u.Path, nil))
+			if w.Code != http.StatusOK {
+				t.Fatal("the thumb is not served", w.Code)
+			}
+		case "ABC-002":
+			if result.ImageUrl != "" {
+				t.Fatal("the thumb failed to cache is linked", result.ImageUrl)
+			}
+		}
+	}
+}
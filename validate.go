@@ -0,0 +1,79 @@
+package scrape
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// MinImageWidth ...
+var MinImageWidth = 64
+
+// MinImageHeight ...
+var MinImageHeight = 64
+
+// ImagePlaceholderURLs an image url(or the url it redirects to) contains one of them is a placeholder
+var ImagePlaceholderURLs = []string{"now_printing", "nowprinting", "noimage", "no_image"}
+
+// image errors ...
+var (
+	ErrEmptyImage       = errors.New("empty image")
+	ErrNotImage         = errors.New("not an image")
+	ErrPlaceholderImage = errors.New("placeholder image")
+	ErrSmallImage       = errors.New("image is too small")
+)
+
+var placeholderLock sync.RWMutex
+var placeholderHashes = make(map[string]bool)
+var placeholderSizes = make(map[image.Point]bool)
+
+// RegisterPlaceholderHash the sha256 of a known placeholder image
+func RegisterPlaceholderHash(hash string) {
+	placeholderLock.Lock()
+	defer placeholderLock.Unlock()
+	placeholderHashes[strings.ToLower(hash)] = true
+}
+
+// RegisterPlaceholderSize the size of a known placeholder image
+func RegisterPlaceholderSize(width, height int) {
+	placeholderLock.Lock()
+	defer placeholderLock.Unlock()
+	placeholderSizes[image.Pt(width, height)] = true
+}
+
+// ValidateImage check the data is a real image,
+// contentType and location are the response header and the final url,both can be empty
+func ValidateImage(bys []byte, contentType string, location string) error {
+	if len(bys) == 0 {
+		return ErrEmptyImage
+	}
+	if contentType != "" && !strings.HasPrefix(contentType, "image/") && !strings.Contains(contentType, "octet-stream") {
+		return fmt.Errorf("%w: content type %s", ErrNotImage, contentType)
+	}
+	if sniff := http.DetectContentType(bys); strings.HasPrefix(sniff, "text/") {
+		return fmt.Errorf("%w: content type %s", ErrNotImage, sniff)
+	}
+	for _, v := range ImagePlaceholderURLs {
+		if strings.Contains(strings.ToLower(location), v) {
+			return fmt.Errorf("%w: %s", ErrPlaceholderImage, location)
+		}
+	}
+	config, _, e := image.DecodeConfig(bytes.NewReader(bys))
+	if e != nil {
+		return fmt.Errorf("%w: %v", ErrNotImage, e)
+	}
+	placeholderLock.RLock()
+	placeholder := placeholderHashes[Hash(string(bys))] || placeholderSizes[image.Pt(config.Width, config.Height)]
+	placeholderLock.RUnlock()
+	if placeholder {
+		return fmt.Errorf("%w: %dx%d", ErrPlaceholderImage, config.Width, config.Height)
+	}
+	if config.Width < MinImageWidth || config.Height < MinImageHeight {
+		return fmt.Errorf("%w: %dx%d", ErrSmallImage, config.Width, config.Height)
+	}
+	return nil
+}
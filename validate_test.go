@@ -0,0 +1,44 @@
+package scrape
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/png"
+	"testing"
+)
+
+func validateTestImage(t *testing.T, width, height int) []byte {
+	buf := bytes.NewBuffer(nil)
+	if e := png.Encode(buf, image.NewRGBA(image.Rect(0, 0, width, height))); e != nil {
+		t.Fatal(e)
+	}
+	return buf.Bytes()
+}
+
+// TestValidateImage ...
+func TestValidateImage(t *testing.T) {
+	img := validateTestImage(t, 100, 100)
+	for name, v := range map[string]struct {
+		bys         []byte
+		contentType string
+		location    string
+		err         error
+	}{
+		"valid":       {img, "image/png", "https://example.com/a.png", nil},
+		"empty":       {nil, "image/jpeg", "", ErrEmptyImage},
+		"html":        {[]byte("<html><body>hotlink blocked</body></html>"), "", "", ErrNotImage},
+		"html header": {img, "text/html", "", ErrNotImage},
+		"placeholder": {img, "", "https://pics.dmm.co.jp/mono/noimage/movie/adult/ps.jpg", ErrPlaceholderImage},
+		"small":       {validateTestImage(t, 10, 10), "", "", ErrSmallImage},
+	} {
+		if e := ValidateImage(v.bys, v.contentType, v.location); !errors.Is(e, v.err) {
+			t.Errorf("%s: got %v want %v", name, e, v.err)
+		}
+	}
+	RegisterPlaceholderSize(100, 100)
+	defer delete(placeholderSizes, image.Pt(100, 100))
+	if e := ValidateImage(img, "", ""); !errors.Is(e, ErrPlaceholderImage) {
+		t.Error(e)
+	}
+}
@@ -0,0 +1,444 @@
+package scrape
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/goextension/log"
+)
+
+// DefaultWatchExts ...
+var DefaultWatchExts = []string{".mp4", ".mkv", ".avi", ".wmv", ".mov", ".m4v", ".ts", ".rmvb", ".flv"}
+
+// DefaultWatchStateName the state file in the first watched directory
+var DefaultWatchStateName = ".watch"
+
+// DefaultWatchRetry a failed file is tried again after the duration,doubled on every failure up to a day
+var DefaultWatchRetry = time.Hour
+
+const watchRetryMax = 24 * time.Hour
+
+// WatchCallback return the output option of the scraped file,nil uses the default option
+type WatchCallback func(path string, key string, content Content) *OutputInfo
+
+// WatchState ...
+type WatchState struct {
+	ID       string
+	Time     time.Time
+	Error    string    `json:",omitempty"` //the last error,the file is scraped again after Retry
+	Failures int       `json:",omitempty"`
+	Retry    time.Time `json:",omitempty"`
+}
+
+// failed check the file is not scraped yet
+func (s *WatchState) failed() bool {
+	return s.Error != ""
+}
+
+// Watcher scrape the new files of the watched directories
+type Watcher struct {
+	lock     sync.Mutex
+	scrape   IScrape
+	dirs     []string
+	exts     []string
+	debounce time.Duration
+	interval time.Duration
+	retry    time.Duration
+	polling  bool
+	state    string
+	states   map[string]*WatchState
+	pending  map[string]*watchPending
+	callback WatchCallback
+	library  *Library
+	stop     chan struct{}
+}
+
+type watchPending struct {
+	size int64
+	last time.Time
+}
+
+// WatchOptions ...
+type WatchOptions func(w *Watcher)
+
+// WatchExts set the file extensions to scrape
+func WatchExts(exts ...string) WatchOptions {
+	return func(w *Watcher) {
+		w.exts = exts
+	}
+}
+
+// WatchDebounce a file is scraped after its size is unchanged for the duration
+func WatchDebounce(d time.Duration) WatchOptions {
+	return func(w *Watcher) {
+		w.debounce = d
+	}
+}
+
+// WatchPolling scan the directories every interval instead of using fsnotify
+func WatchPolling(interval time.Duration) WatchOptions {
+	return func(w *Watcher) {
+		w.polling = true
+		w.interval = interval
+	}
+}
+
+// WatchRetry the first delay before a failed file is scraped again
+func WatchRetry(d time.Duration) WatchOptions {
+	return func(w *Watcher) {
+		w.retry = d
+	}
+}
+
+// WatchStateFile ...
+func WatchStateFile(path string) WatchOptions {
+	return func(w *Watcher) {
+		w.state = path
+	}
+}
+
+// WatchCallbackOption ...
+func WatchCallbackOption(f WatchCallback) WatchOptions {
+	return func(w *Watcher) {
+		w.callback = f
+	}
+}
+
+// WatchLibrary map the scraped files to the titles of the library
+func WatchLibrary(library *Library) WatchOptions {
+	return func(w *Watcher) {
+		w.library = library
+	}
+}
+
+// NewWatcher the scrape is cleared before every file,so it should not be shared
+func NewWatcher(scrape IScrape, dirs []string, opts ...WatchOptions) *Watcher {
+	//the states are kept by the absolute paths,the working directory may change between the runs
+	var abs []string
+	for _, dir := range dirs {
+		if v, e := filepath.Abs(dir); e == nil {
+			dir = v
+		}
+		abs = append(abs, dir)
+	}
+	state := DefaultWatchStateName
+	if len(abs) > 0 {
+		state = filepath.Join(abs[0], DefaultWatchStateName)
+	}
+	w := &Watcher{
+		scrape:   scrape,
+		dirs:     abs,
+		exts:     DefaultWatchExts,
+		debounce: 30 * time.Second,
+		interval: time.Minute,
+		retry:    DefaultWatchRetry,
+		state:    state,
+		states:   make(map[string]*WatchState),
+		pending:  make(map[string]*watchPending),
+		callback: defaultWatchCallback,
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+// defaultWatchCallback output the info and images next to the file
+func defaultWatchCallback(path string, key string, content Content) *OutputInfo {
+	option := DefaultOutputOption()
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	option.OutputPath = filepath.Dir(path)
+	option.SourceFile = path
+	option.ImagePath = ""
+	option.CopyInfo = true
+	option.InfoName = name
+	option.PosterName = name + "-poster"
+	option.ThumbName = name + "-thumb"
+	return option
+}
+
+// Start scrape the existing files and watch the directories until Stop
+func (w *Watcher) Start() error {
+	w.lock.Lock()
+	if w.stop != nil {
+		w.lock.Unlock()
+		return errors.New("watcher is started")
+	}
+	stop := make(chan struct{})
+	w.stop = stop
+	w.lock.Unlock()
+
+	e := w.loadState()
+	if e != nil {
+		w.Stop()
+		return e
+	}
+
+	var watcher *fsnotify.Watcher
+	var events <-chan fsnotify.Event
+	var errs <-chan error
+	if !w.polling {
+		watcher, e = w.notify()
+		if e != nil {
+			log.Warnw("watch", "polling", w.interval, "error", e)
+			w.polling = true
+		} else {
+			defer watcher.Close()
+			events = watcher.Events
+			errs = watcher.Errors
+		}
+	}
+
+	w.scan(w.dirs...)
+	tick := time.NewTicker(w.tick())
+	defer tick.Stop()
+	scan := time.Now()
+	for {
+		select {
+		case <-stop:
+			return nil
+		case event := <-events:
+			if event.Op&fsnotify.Create != 0 {
+				if info, e := os.Stat(event.Name); e == nil && info.IsDir() {
+					w.notifyDir(watcher, event.Name)
+					w.scan(event.Name)
+					continue
+				}
+			}
+			if event.Op&(fsnotify.Create|fsnotify.Write) != 0 {
+				w.touch(event.Name)
+			}
+		case e := <-errs:
+			log.Errorw("watch", "error", e)
+		case now := <-tick.C:
+			if w.polling && now.Sub(scan) >= w.interval {
+				scan = now
+				w.scan(w.dirs...)
+			}
+			w.process(now)
+		}
+	}
+}
+
+// Stop ...
+func (w *Watcher) Stop() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.stop != nil {
+		close(w.stop)
+		w.stop = nil
+	}
+}
+
+func (w *Watcher) tick() time.Duration {
+	d := w.debounce / 2
+	if w.polling && w.interval < d {
+		d = w.interval
+	}
+	if d < time.Second {
+		d = time.Second
+	}
+	return d
+}
+
+func (w *Watcher) notify() (*fsnotify.Watcher, error) {
+	watcher, e := fsnotify.NewWatcher()
+	if e != nil {
+		return nil, e
+	}
+	for _, dir := range w.dirs {
+		e = w.notifyDir(watcher, dir)
+		if e != nil {
+			watcher.Close()
+			return nil, e
+		}
+	}
+	return watcher, nil
+}
+
+// notifyDir add the directory and its sub directories to the watcher
+func (w *Watcher) notifyDir(watcher *fsnotify.Watcher, dir string) error {
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return watcher.Add(path)
+		}
+		return nil
+	})
+}
+
+func (w *Watcher) scan(dirs ...string) {
+	for _, dir := range dirs {
+		e := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() {
+				w.touch(path)
+			}
+			return nil
+		})
+		if e != nil {
+			log.Errorw("watch", "dir", dir, "error", e)
+		}
+	}
+}
+
+func (w *Watcher) match(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, v := range w.exts {
+		if ext == v {
+			return true
+		}
+	}
+	return false
+}
+
+// touch add the file to the pending list,the debounce restarts when the size changes
+func (w *Watcher) touch(path string) {
+	if !w.match(path) {
+		return
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if s, b := w.states[path]; b && (!s.failed() || time.Now().Before(s.Retry)) {
+		return
+	}
+	info, e := os.Stat(path)
+	if e != nil || info.IsDir() {
+		return
+	}
+	p, b := w.pending[path]
+	if !b || p.size != info.Size() {
+		w.pending[path] = &watchPending{
+			size: info.Size(),
+			last: time.Now(),
+		}
+	}
+}
+
+func (w *Watcher) process(now time.Time) {
+	var paths []string
+	w.lock.Lock()
+	for path, p := range w.pending {
+		info, e := os.Stat(path)
+		if e != nil {
+			delete(w.pending, path)
+			continue
+		}
+		if info.Size() != p.size {
+			p.size = info.Size()
+			p.last = now
+			continue
+		}
+		if now.Sub(p.last) >= w.debounce {
+			delete(w.pending, path)
+			paths = append(paths, path)
+		}
+	}
+	w.lock.Unlock()
+
+	for _, path := range paths {
+		id, e := w.scrapeFile(path)
+		state := &WatchState{
+			ID:   id,
+			Time: now,
+		}
+		w.lock.Lock()
+		if e != nil {
+			//the failed file waits longer every time instead of querying the sites on every scan
+			state.Error = e.Error()
+			state.Failures = 1
+			if old, b := w.states[path]; b {
+				state.Failures = old.Failures + 1
+			}
+			state.Retry = now.Add(w.retryDelay(state.Failures))
+			log.Errorw("watch", "path", path, "retry", state.Retry, "error", e)
+		}
+		w.states[path] = state
+		w.lock.Unlock()
+		e = w.saveState()
+		if e != nil {
+			log.Errorw("watch", "state", w.state, "error", e)
+		}
+	}
+}
+
+// retryDelay the delay after the failures of a file
+func (w *Watcher) retryDelay(failures int) time.Duration {
+	d := w.retry
+	for i := 1; i < failures && d < watchRetryMax; i++ {
+		d *= 2
+	}
+	if d > watchRetryMax {
+		d = watchRetryMax
+	}
+	return d
+}
+
+// scrapeFile the file is done when the id of the file is found and output
+func (w *Watcher) scrapeFile(path string) (string, error) {
+	id := ParseID(path)
+	if id == "" {
+		return "", fmt.Errorf("no id in the file name: %w", ErrNotFound)
+	}
+	w.scrape.Clear()
+	e := w.scrape.Find(id)
+	if e != nil {
+		return id, e
+	}
+	infos := w.scrape.OutputCallback(func(key string, content Content) *OutputInfo {
+		//a search may also find the neighbouring ids
+		if !strings.EqualFold(key, id) {
+			return &OutputInfo{Skip: true}
+		}
+		return w.callback(path, key, content)
+	})
+	if debug {
+		log.Infow("watch", "path", path, "id", id, "output", infos)
+	}
+	if len(infos) == 0 {
+		return id, fmt.Errorf("%s: %w", id, ErrNotFound)
+	}
+	if w.library != nil {
+		e = w.library.AddPath(id, path)
+		if e != nil {
+			log.Errorw("watch", "path", path, "id", id, "error", e)
+		}
+	}
+	return id, nil
+}
+
+func (w *Watcher) loadState() error {
+	bys, e := ioutil.ReadFile(w.state)
+	if e != nil {
+		if os.IsNotExist(e) {
+			return nil
+		}
+		return e
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return json.Unmarshal(bys, &w.states)
+}
+
+func (w *Watcher) saveState() error {
+	w.lock.Lock()
+	bys, e := json.MarshalIndent(w.states, "", " ")
+	w.lock.Unlock()
+	if e != nil {
+		return e
+	}
+	_ = os.MkdirAll(filepath.Dir(w.state), os.ModePerm)
+	return ioutil.WriteFile(w.state, bys, 0644)
+}
@@ -0,0 +1,90 @@
+package scrape
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestWatcher ...
+func TestWatcher(t *testing.T) {
+	dir, e := ioutil.TempDir("", "watch")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "abc-001.mp4")
+	e = ioutil.WriteFile(path, []byte("video"), 0755)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	impl := &scrapeImpl{
+		contents: make(map[string][]Content),
+		grabs:    []IGrab{&searchGrabTest{name: "test", pages: 1}},
+	}
+	w := NewWatcher(impl, []string{dir}, WatchDebounce(0), WatchStateFile(filepath.Join(dir, ".watch")))
+	w.scan(dir)
+	w.process(time.Now())
+	if s, b := w.states[path]; !b || s.ID != "ABC-001" {
+		t.Fatalf("state of %s: %+v", path, s)
+	}
+	nfo, e := ioutil.ReadFile(filepath.Join(dir, "abc-001.nfo"))
+	if e != nil {
+		t.Fatal(e)
+	}
+	//the search also finds ABC-000,which is not written for this file
+	if !strings.Contains(string(nfo), "ABC-001") || strings.Contains(string(nfo), "ABC-000") {
+		t.Fatal(string(nfo))
+	}
+	info, e := os.Stat(filepath.Join(dir, ".watch"))
+	if e != nil || info.Mode().Perm() != 0644 {
+		t.Fatal(info, e)
+	}
+
+	//a file without an id or with an id not found is recorded as failed and tried again later
+	for _, name := range []string{"video.mp4", "xyz-123.mp4"} {
+		e = ioutil.WriteFile(filepath.Join(dir, name), []byte("video"), 0644)
+		if e != nil {
+			t.Fatal(e)
+		}
+	}
+	w.scan(dir)
+	w.process(time.Now())
+	failed := w.states[filepath.Join(dir, "xyz-123.mp4")]
+	if len(w.states) != 3 || failed == nil || !failed.failed() || failed.ID != "XYZ-123" || failed.Failures != 1 {
+		t.Fatal(w.states)
+	}
+	w.scan(dir)
+	if len(w.pending) != 0 {
+		t.Fatal("failed file is pending before the retry", w.pending)
+	}
+
+	//the failures are kept over the runs and the retry time is doubled
+	w = NewWatcher(impl, []string{dir}, WatchDebounce(0))
+	if w.state != filepath.Join(dir, DefaultWatchStateName) {
+		t.Fatal(w.state)
+	}
+	if e := w.loadState(); e != nil {
+		t.Fatal(e)
+	}
+	for _, s := range w.states {
+		s.Retry = time.Time{}
+	}
+	w.scan(dir)
+	if _, b := w.pending[path]; b || len(w.pending) != 2 {
+		t.Fatal("scraped file is pending again", w.pending)
+	}
+	now := time.Now()
+	w.process(now)
+	failed = w.states[filepath.Join(dir, "xyz-123.mp4")]
+	if failed.Failures != 2 || !failed.Retry.Equal(now.Add(2*DefaultWatchRetry)) {
+		t.Fatalf("%+v", failed)
+	}
+	if d := w.retryDelay(100); d != watchRetryMax {
+		t.Fatal(d)
+	}
+}